@@ -36,7 +36,7 @@ func main() {
 	defer pool.Close()
 
 	// 添加客户端到连接池
-	err := pool.Add("main_client",
+	err := pool.Add(context.Background(), "main_client",
 		client.WithAddress(address),
 		client.WithAuth(username, password),
 		client.WithDatabase(dbName),
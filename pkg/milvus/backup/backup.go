@@ -0,0 +1,272 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/export"
+)
+
+// defaultPartition Milvus默认分区名称
+const defaultPartition = "_default"
+
+// Options 定义备份与恢复的配置选项
+type Options struct {
+	Collections []string // 要备份或恢复的集合名称（备份中的原始名称），空表示全部
+	BatchSize   int      // 导出分页与恢复插入的批大小，默认1000
+	SchemaOnly  bool     // 只处理元数据（模式、分区、索引、别名），不处理数据
+
+	// 以下选项仅用于恢复
+	TargetDatabase string            // 恢复到的数据库，空表示使用备份中记录的数据库，不存在时自动创建
+	Rename         map[string]string // 集合重命名映射，原名称 -> 新名称
+	DropExisting   bool              // 目标集合已存在时先删除，否则返回错误
+	SkipAliases    bool              // 不恢复别名，恢复到同一数据库的副本时可避免别名冲突
+}
+
+// DefaultOptions 返回默认配置
+func DefaultOptions() *Options {
+	return &Options{
+		BatchSize: 1000,
+	}
+}
+
+// Option 定义配置选项函数类型
+type Option func(*Options)
+
+// WithCollections 设置要备份或恢复的集合
+func WithCollections(names ...string) Option {
+	return func(o *Options) {
+		o.Collections = names
+	}
+}
+
+// WithBatchSize 设置批大小
+func WithBatchSize(batchSize int) Option {
+	return func(o *Options) {
+		o.BatchSize = batchSize
+	}
+}
+
+// WithSchemaOnly 设置只处理元数据
+func WithSchemaOnly() Option {
+	return func(o *Options) {
+		o.SchemaOnly = true
+	}
+}
+
+// WithTargetDatabase 设置恢复到的数据库
+func WithTargetDatabase(dbName string) Option {
+	return func(o *Options) {
+		o.TargetDatabase = dbName
+	}
+}
+
+// WithRename 设置恢复时的集合重命名，可多次调用
+func WithRename(from, to string) Option {
+	return func(o *Options) {
+		if o.Rename == nil {
+			o.Rename = make(map[string]string)
+		}
+		o.Rename[from] = to
+	}
+}
+
+// WithDropExisting 设置恢复时删除已存在的目标集合
+func WithDropExisting() Option {
+	return func(o *Options) {
+		o.DropExisting = true
+	}
+}
+
+// WithSkipAliases 设置恢复时不创建别名
+func WithSkipAliases() Option {
+	return func(o *Options) {
+		o.SkipAliases = true
+	}
+}
+
+func newOptions(opts []Option) *Options {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultOptions().BatchSize
+	}
+	return options
+}
+
+// selected 判断集合是否在选择范围内
+func (o *Options) selected(name string) bool {
+	if len(o.Collections) == 0 {
+		return true
+	}
+	for _, n := range o.Collections {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Backup 备份数据库到目录
+// 目录中包含清单文件manifest.json以及每个集合、每个分区一个的JSON Lines数据文件；
+// 未加载的集合会在导出数据期间临时加载，完成后释放
+// 注意：该方法会将客户端切换到dbName数据库
+// ctx: 上下文，用于控制请求生命周期
+// cli: Milvus客户端
+// dbName: 要备份的数据库名称，例如"default"
+// dir: 备份目录，不存在时自动创建，例如"/data/backup/20240101"
+// opts: 配置选项列表
+// 返回值: (备份清单, 错误信息)
+func Backup(ctx context.Context, cli client.Client, dbName string, dir string, opts ...Option) (*Manifest, error) {
+	options := newOptions(opts)
+	if dbName == "" {
+		return nil, errors.New("database name is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "failed to create backup directory")
+	}
+	if err := cli.UseDatabase(ctx, dbName); err != nil {
+		return nil, errors.Wrapf(err, "failed to use database %s", dbName)
+	}
+
+	names := options.Collections
+	if len(names) == 0 {
+		var err error
+		if names, err = cli.ListCollections(ctx); err != nil {
+			return nil, errors.Wrap(err, "failed to list collections")
+		}
+	}
+
+	manifest := &Manifest{
+		Version:   ManifestVersion,
+		CreatedAt: time.Now(),
+		Database:  dbName,
+	}
+	for _, name := range names {
+		cm, err := backupCollection(ctx, cli, dir, name, options)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to backup collection %s", name)
+		}
+		manifest.Collections = append(manifest.Collections, *cm)
+	}
+
+	if err := writeManifest(dir, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// backupCollection 备份单个集合的元数据与数据
+func backupCollection(ctx context.Context, cli client.Client, dir string, name string, options *Options) (*CollectionManifest, error) {
+	coll, err := cli.DescribeCollection(ctx, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe collection")
+	}
+	// 函数（如BM25）的输出字段由服务端生成，无法导出后再写入，模式头也不记录函数
+	if len(coll.Schema.Functions) > 0 {
+		return nil, errors.Errorf("collections with functions are not supported, found function %s", coll.Schema.Functions[0].Name)
+	}
+	partitions, err := cli.ListPartitions(ctx, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list partitions")
+	}
+	aliases, err := cli.ListAliases(ctx, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list aliases")
+	}
+
+	cm := &CollectionManifest{
+		Name:             name,
+		Schema:           export.NewSchemaHeader(coll.Schema, nil),
+		ShardNum:         coll.ShardNum,
+		ConsistencyLevel: int32(coll.ConsistencyLevel),
+		Properties:       coll.Properties,
+		Loaded:           coll.Loaded,
+		Partitions:       partitions,
+		Aliases:          aliases,
+	}
+	if cm.Schema.Collection == "" {
+		cm.Schema.Collection = name
+	}
+
+	for _, field := range coll.Schema.Fields {
+		idx, err := cli.DescribeIndex(ctx, name, field.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe index on field %s", field.Name)
+		}
+		if idx == nil {
+			continue
+		}
+		cm.Indexes = append(cm.Indexes, IndexManifest{
+			Field:  field.Name,
+			Name:   idx.Name(),
+			Params: idx.Params(),
+		})
+	}
+
+	if options.SchemaOnly {
+		return cm, nil
+	}
+
+	// 查询数据要求集合已加载
+	if !coll.Loaded {
+		if err := cli.LoadCollection(ctx, name); err != nil {
+			return nil, errors.Wrap(err, "failed to load collection")
+		}
+		defer cli.ReleaseCollection(context.Background(), name)
+	}
+
+	// 使用分区键的集合由服务端管理分区，整体导出
+	targets := partitions
+	if hasPartitionKey(cm.Schema) {
+		targets = []string{""}
+	}
+	for _, partition := range targets {
+		file := DataFile{
+			Partition: partition,
+			Path:      dataFilePath(name, partition),
+		}
+		fullPath := filepath.Join(dir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			return nil, errors.Wrap(err, "failed to create data directory")
+		}
+		exportOpts := []export.Option{export.WithBatchSize(options.BatchSize)}
+		if partition != "" {
+			exportOpts = append(exportOpts, export.WithPartitions(partition))
+		}
+		exporter := export.NewExporter(cli, exportOpts...)
+		if file.Rows, err = exporter.ExportFile(ctx, name, export.FormatJSONL, fullPath); err != nil {
+			return nil, errors.Wrapf(err, "failed to export partition %s", partition)
+		}
+		cm.Files = append(cm.Files, file)
+		cm.RowCount += file.Rows
+	}
+	return cm, nil
+}
+
+// hasPartitionKey 判断集合是否使用分区键
+func hasPartitionKey(header *export.SchemaHeader) bool {
+	for _, f := range header.Fields {
+		if f.IsPartitionKey {
+			return true
+		}
+	}
+	return false
+}
+
+// dataFilePath 返回数据文件相对于备份目录的路径
+// 整体导出的集合为 <集合>/data.jsonl，按分区导出的为 <集合>/partitions/<分区>.jsonl
+func dataFilePath(collection, partition string) string {
+	if partition == "" {
+		return path.Join(collection, "data.jsonl")
+	}
+	return path.Join(collection, "partitions", partition+".jsonl")
+}
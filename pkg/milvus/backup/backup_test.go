@@ -0,0 +1,183 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/export"
+)
+
+const (
+	// 测试配置
+	testAddress  = "192.168.103.113:19530"
+	testUsername = "root"
+	testPassword = ""
+)
+
+// TestManifest 测试清单的读写
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	m := &Manifest{
+		Version:   ManifestVersion,
+		CreatedAt: time.Now().Truncate(time.Second),
+		Database:  "default",
+		Collections: []CollectionManifest{{
+			Name:       "c1",
+			Partitions: []string{"_default", "p1"},
+			Indexes:    []IndexManifest{{Field: "vector", Name: "vector", Params: map[string]string{"index_type": "FLAT"}}},
+			Files:      []DataFile{{Partition: "p1", Path: dataFilePath("c1", "p1"), Rows: 3}},
+		}},
+	}
+	require.NoError(t, writeManifest(dir, m))
+
+	got, err := ReadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, m.Database, got.Database)
+	assert.True(t, m.CreatedAt.Equal(got.CreatedAt))
+	require.NotNil(t, got.Collection("c1"))
+	assert.Equal(t, "c1/partitions/p1.jsonl", got.Collection("c1").Files[0].Path)
+	assert.Nil(t, got.Collection("c2"))
+
+	t.Run("不支持的版本", func(t *testing.T) {
+		m.Version = ManifestVersion + 1
+		require.NoError(t, writeManifest(dir, m))
+		_, err := ReadManifest(dir)
+		assert.Error(t, err)
+	})
+}
+
+// TestOptions 测试选项
+func TestOptions(t *testing.T) {
+	options := newOptions([]Option{
+		WithCollections("a", "b"),
+		WithBatchSize(0),
+		WithRename("a", "a_copy"),
+		WithRename("b", "b_copy"),
+	})
+	assert.Equal(t, DefaultOptions().BatchSize, options.BatchSize)
+	assert.True(t, options.selected("a"))
+	assert.False(t, options.selected("c"))
+	assert.Equal(t, map[string]string{"a": "a_copy", "b": "b_copy"}, options.Rename)
+	assert.True(t, newOptions(nil).selected("any"))
+}
+
+// TestSplitDynamic 测试动态字段按键展开
+func TestSplitDynamic(t *testing.T) {
+	ids := column.NewColumnInt64("id", []int64{1, 2, 3})
+	dynamic := column.NewColumnJSONBytes(export.DynamicFieldName, [][]byte{
+		[]byte(`{"a":1}`), []byte(`{"a":2}`), []byte(`{"b":"x"}`),
+	})
+
+	batches, err := splitDynamic([]column.Column{ids}, dynamic)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+
+	first := batches[0]
+	require.Len(t, first, 2)
+	assert.Equal(t, 2, first[0].Len())
+	assert.Equal(t, "a", first[1].Name())
+	value, err := first[1].Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, json.RawMessage("2"), value)
+	// 列数据是实际的JSON值，估算请求大小与拆分时可以读取
+	keyColumn, ok := first[1].(interface{ Data() [][]byte })
+	require.True(t, ok)
+	assert.Equal(t, [][]byte{[]byte("1"), []byte("2")}, keyColumn.Data())
+	assert.Equal(t, [][]byte{[]byte("1"), []byte("2")}, first[1].FieldData().GetScalars().GetJsonData().GetData())
+	sliced := first[1].Slice(1, 2)
+	value, err = sliced.Get(0)
+	require.NoError(t, err)
+	assert.Equal(t, json.RawMessage("2"), value)
+
+	second := batches[1]
+	id, err := second[0].GetAsInt64(0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), id)
+	assert.Equal(t, "b", second[1].Name())
+
+	t.Run("无动态字段", func(t *testing.T) {
+		batches, err := splitDynamic([]column.Column{ids}, nil)
+		require.NoError(t, err)
+		assert.Len(t, batches, 1)
+	})
+}
+
+// TestBackupRestore 测试备份并恢复到新数据库
+func TestBackupRestore(t *testing.T) {
+	connectCtx, connectCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer connectCancel()
+
+	cli, err := client.New(connectCtx, testAddress, testUsername, testPassword)
+	if err != nil {
+		t.Skipf("跳过测试，无法连接到Milvus服务器: %v", err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	collectionName := fmt.Sprintf("test_backup_%d_%d", time.Now().Unix(), rand.Intn(10000))
+	targetDB := fmt.Sprintf("test_restore_db_%d_%d", time.Now().Unix(), rand.Intn(10000))
+
+	schema := &entity.Schema{
+		CollectionName:     collectionName,
+		EnableDynamicField: true,
+		Fields: []*entity.Field{
+			{Name: "id", DataType: entity.FieldTypeInt64, PrimaryKey: true},
+			{Name: "vector", DataType: entity.FieldTypeFloatVector, TypeParams: map[string]string{"dim": "4"}},
+		},
+	}
+	require.NoError(t, cli.UseDatabase(ctx, "default"))
+	require.NoError(t, cli.CreateCollection(ctx, schema, 1))
+	defer func() {
+		cli.UseDatabase(ctx, "default")
+		cli.DropCollection(ctx, collectionName)
+	}()
+	require.NoError(t, cli.CreatePartition(ctx, collectionName, "p1"))
+	require.NoError(t, cli.CreateIndex(ctx, collectionName, "vector", index.NewFlatIndex(entity.L2)))
+
+	_, err = cli.Insert(ctx, collectionName, "p1",
+		column.NewColumnInt64("id", []int64{1, 2, 3}),
+		column.NewColumnFloatVector("vector", 4, [][]float32{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}}),
+		column.NewColumnVarChar("tag", []string{"a", "b", "c"}),
+	)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	manifest, err := Backup(ctx, cli, "default", dir, WithCollections(collectionName))
+	require.NoError(t, err)
+	require.Len(t, manifest.Collections, 1)
+	assert.Equal(t, int64(3), manifest.Collections[0].RowCount)
+	assert.Len(t, manifest.Collections[0].Indexes, 1)
+
+	restored, err := Restore(ctx, cli, dir, WithTargetDatabase(targetDB), WithRename(collectionName, collectionName+"_copy"))
+	require.NoError(t, err)
+	defer func() {
+		cli.UseDatabase(ctx, targetDB)
+		cli.DropCollection(ctx, collectionName+"_copy")
+		cli.UseDatabase(ctx, "default")
+		cli.DropDatabase(ctx, targetDB)
+	}()
+	require.Len(t, restored.Collections, 1)
+	assert.Equal(t, collectionName+"_copy", restored.Collections[0].Name)
+	assert.Equal(t, int64(3), restored.Collections[0].RowCount)
+
+	has, err := cli.HasPartition(ctx, collectionName+"_copy", "p1")
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	coll, err := cli.DescribeCollection(ctx, collectionName+"_copy")
+	require.NoError(t, err)
+	assert.Equal(t, entity.ConsistencyLevel(manifest.Collections[0].ConsistencyLevel), coll.ConsistencyLevel)
+}
@@ -0,0 +1,91 @@
+package backup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/export"
+)
+
+// ManifestVersion 当前备份清单的格式版本
+const ManifestVersion = 1
+
+// ManifestFile 备份目录中清单文件的名称
+const ManifestFile = "manifest.json"
+
+// Manifest 备份清单，描述备份目录中的数据库、集合及数据文件
+type Manifest struct {
+	Version     int                  `json:"version"`
+	CreatedAt   time.Time            `json:"created_at"`
+	Database    string               `json:"database"`
+	Collections []CollectionManifest `json:"collections"`
+}
+
+// CollectionManifest 单个集合的备份信息
+type CollectionManifest struct {
+	Name             string               `json:"name"`
+	Schema           *export.SchemaHeader `json:"schema"`
+	ShardNum         int32                `json:"shard_num"`
+	ConsistencyLevel int32                `json:"consistency_level"`
+	Properties       map[string]string    `json:"properties,omitempty"`
+	Loaded           bool                 `json:"loaded"`
+	Partitions       []string             `json:"partitions"`
+	Aliases          []string             `json:"aliases,omitempty"`
+	Indexes          []IndexManifest      `json:"indexes,omitempty"`
+	Files            []DataFile           `json:"files,omitempty"`
+	RowCount         int64                `json:"row_count"`
+}
+
+// IndexManifest 字段索引的备份信息
+type IndexManifest struct {
+	Field  string            `json:"field"`
+	Name   string            `json:"name"`
+	Params map[string]string `json:"params"`
+}
+
+// DataFile 数据文件信息，文件格式为export包的JSON Lines
+type DataFile struct {
+	Partition string `json:"partition,omitempty"` // 分区名称，空表示整个集合（例如使用分区键的集合）
+	Path      string `json:"path"`                // 相对于备份目录的路径
+	Rows      int64  `json:"rows"`
+}
+
+// Collection 按名称查找集合的备份信息，不存在时返回nil
+func (m *Manifest) Collection(name string) *CollectionManifest {
+	for i := range m.Collections {
+		if m.Collections[i].Name == name {
+			return &m.Collections[i]
+		}
+	}
+	return nil
+}
+
+// ReadManifest 读取备份目录中的清单
+// dir: 备份目录，例如"/data/backup/20240101"
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrap(err, "failed to decode manifest")
+	}
+	if m.Version > ManifestVersion {
+		return nil, errors.Errorf("unsupported manifest version %d", m.Version)
+	}
+	return &m, nil
+}
+
+// writeManifest 将清单写入备份目录
+func writeManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode manifest")
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFile), data, 0o644)
+}
@@ -0,0 +1,307 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/pkg/errors"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/export"
+)
+
+// Restore 从备份目录恢复数据库
+// 可通过选项恢复到其他数据库（或传入连接其他集群的客户端）、重命名集合、只恢复部分集合；
+// 自动生成主键（AutoID）的集合恢复后主键会重新生成；重命名的集合不恢复别名
+// 注意：该方法会将客户端切换到目标数据库
+// ctx: 上下文，用于控制请求生命周期
+// cli: 目标Milvus客户端
+// dir: 备份目录，例如"/data/backup/20240101"
+// opts: 配置选项列表
+// 返回值: (实际恢复的集合清单，集合名称为恢复后的名称, 错误信息)
+func Restore(ctx context.Context, cli client.Client, dir string, opts ...Option) (*Manifest, error) {
+	options := newOptions(opts)
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	dbName := options.TargetDatabase
+	if dbName == "" {
+		dbName = manifest.Database
+	}
+	if err := ensureDatabase(ctx, cli, dbName); err != nil {
+		return nil, err
+	}
+
+	restored := &Manifest{
+		Version:   manifest.Version,
+		CreatedAt: manifest.CreatedAt,
+		Database:  dbName,
+	}
+	for _, cm := range manifest.Collections {
+		if !options.selected(cm.Name) {
+			continue
+		}
+		target := cm.Name
+		if newName, ok := options.Rename[cm.Name]; ok && newName != "" {
+			target = newName
+		}
+		result, err := restoreCollection(ctx, cli, dir, cm, target, options)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to restore collection %s as %s", cm.Name, target)
+		}
+		restored.Collections = append(restored.Collections, *result)
+	}
+	return restored, nil
+}
+
+// ensureDatabase 确保数据库存在并切换到该数据库
+func ensureDatabase(ctx context.Context, cli client.Client, dbName string) error {
+	databases, err := cli.ListDatabases(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list databases")
+	}
+	exists := false
+	for _, db := range databases {
+		if db == dbName {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		if err := cli.CreateDatabase(ctx, dbName); err != nil {
+			return errors.Wrapf(err, "failed to create database %s", dbName)
+		}
+	}
+	if err := cli.UseDatabase(ctx, dbName); err != nil {
+		return errors.Wrapf(err, "failed to use database %s", dbName)
+	}
+	return nil
+}
+
+// restoreCollection 恢复单个集合：模式、分区、索引、别名、数据与加载状态
+func restoreCollection(ctx context.Context, cli client.Client, dir string, cm CollectionManifest, target string, options *Options) (*CollectionManifest, error) {
+	exists, err := cli.HasCollection(ctx, target)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check collection")
+	}
+	if exists {
+		if !options.DropExisting {
+			return nil, errors.Errorf("collection %s already exists", target)
+		}
+		if err := cli.DropCollection(ctx, target); err != nil {
+			return nil, errors.Wrap(err, "failed to drop existing collection")
+		}
+	}
+
+	schema, err := cm.Schema.Schema()
+	if err != nil {
+		return nil, err
+	}
+	schema.CollectionName = target
+	if err := createCollection(ctx, cli, schema, cm); err != nil {
+		return nil, errors.Wrap(err, "failed to create collection")
+	}
+
+	if !hasPartitionKey(cm.Schema) {
+		for _, partition := range cm.Partitions {
+			if partition == defaultPartition {
+				continue
+			}
+			if err := cli.CreatePartition(ctx, target, partition); err != nil {
+				return nil, errors.Wrapf(err, "failed to create partition %s", partition)
+			}
+		}
+	}
+
+	for _, idx := range cm.Indexes {
+		if err := cli.CreateIndex(ctx, target, idx.Field, index.NewGenericIndex(idx.Name, idx.Params)); err != nil {
+			return nil, errors.Wrapf(err, "failed to create index on field %s", idx.Field)
+		}
+	}
+
+	result := cm
+	result.Name = target
+	result.RowCount = 0
+	result.Files = nil
+
+	// 在写入数据前创建别名，别名冲突时尽早失败；重命名的集合不恢复别名，别名仍属于原集合
+	if options.SkipAliases || target != cm.Name {
+		result.Aliases = nil
+	}
+	for _, alias := range result.Aliases {
+		if err := cli.CreateAlias(ctx, target, alias); err != nil {
+			return nil, errors.Wrapf(err, "failed to create alias %s", alias)
+		}
+	}
+
+	if !options.SchemaOnly {
+		for _, file := range cm.Files {
+			rows, err := restoreFile(ctx, cli, dir, target, file, options.BatchSize)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to restore %s", file.Path)
+			}
+			file.Rows = rows
+			result.Files = append(result.Files, file)
+			result.RowCount += rows
+		}
+	}
+
+	if cm.Loaded {
+		if err := cli.LoadCollection(ctx, target); err != nil {
+			return nil, errors.Wrap(err, "failed to load collection")
+		}
+	}
+	return &result, nil
+}
+
+// createCollection 按备份的分片数、一致性级别与集合属性创建集合
+func createCollection(ctx context.Context, cli client.Client, schema *entity.Schema, cm CollectionManifest) error {
+	opts := []client.CollectionOption{
+		client.WithShardNum(cm.ShardNum),
+		client.WithConsistencyLevel(entity.ConsistencyLevel(cm.ConsistencyLevel)),
+	}
+	for key, value := range cm.Properties {
+		opts = append(opts, client.WithCollectionProperty(key, value))
+	}
+	return cli.CreateCollectionWithOptions(ctx, schema, opts...)
+}
+
+// restoreFile 将数据文件分批插入目标集合
+func restoreFile(ctx context.Context, cli client.Client, dir string, collection string, file DataFile, batchSize int) (int64, error) {
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(file.Path)))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	reader, err := export.NewJSONLReader(f)
+	if err != nil {
+		return 0, err
+	}
+
+	// 自动生成的主键由服务端重新分配，不能随数据写入
+	skip := make(map[string]bool)
+	if pk := reader.Header().PrimaryField(); pk != nil && (pk.AutoID || reader.Header().AutoID) {
+		skip[pk.Name] = true
+	}
+
+	var total int64
+	for {
+		columns, err := reader.Read(batchSize)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+
+		var fixed []column.Column
+		var dynamic column.Column
+		for _, col := range columns {
+			switch {
+			case skip[col.Name()]:
+			case col.Name() == export.DynamicFieldName:
+				dynamic = col
+			default:
+				fixed = append(fixed, col)
+			}
+		}
+
+		batches, err := splitDynamic(fixed, dynamic)
+		if err != nil {
+			return total, err
+		}
+		for _, batch := range batches {
+			if _, err := cli.Insert(ctx, collection, file.Partition, batch...); err != nil {
+				return total, errors.Wrap(err, "failed to insert rows")
+			}
+			total += int64(batch[0].Len())
+		}
+	}
+}
+
+// splitDynamic 将动态字段列展开为每个键一列，由SDK在插入时合并回动态字段
+// 连续且键集合相同的行作为一批插入，避免为缺失的键写入null
+func splitDynamic(fixed []column.Column, dynamic column.Column) ([][]column.Column, error) {
+	if dynamic == nil {
+		return [][]column.Column{fixed}, nil
+	}
+
+	n := dynamic.Len()
+	rows := make([]map[string]json.RawMessage, n)
+	signatures := make([]string, n)
+	for i := 0; i < n; i++ {
+		value, err := dynamic.Get(i)
+		if err != nil {
+			return nil, err
+		}
+		if data, ok := value.([]byte); ok && len(data) > 0 {
+			if err := json.Unmarshal(data, &rows[i]); err != nil {
+				return nil, errors.Wrap(err, "failed to decode dynamic field")
+			}
+		}
+		keys := make([]string, 0, len(rows[i]))
+		for k := range rows[i] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		signatures[i] = strings.Join(keys, "\x00")
+	}
+
+	var batches [][]column.Column
+	for start := 0; start < n; {
+		end := start + 1
+		for end < n && signatures[end] == signatures[start] {
+			end++
+		}
+
+		batch := make([]column.Column, 0, len(fixed)+len(rows[start]))
+		for _, col := range fixed {
+			batch = append(batch, col.Slice(start, end))
+		}
+		for key := range rows[start] {
+			values := make([][]byte, 0, end-start)
+			for i := start; i < end; i++ {
+				values = append(values, rows[i][key])
+			}
+			batch = append(batch, newDynamicKeyColumn(key, values))
+		}
+		batches = append(batches, batch)
+		start = end
+	}
+	return batches, nil
+}
+
+// dynamicKeyColumn 动态字段中单个键对应的列，每行为该键的原始JSON值
+// SDK在插入时通过Get合并动态字段，JSON列的Get返回[]byte会被编码为base64字符串，因此返回json.RawMessage
+type dynamicKeyColumn struct {
+	*column.ColumnJSONBytes
+}
+
+func newDynamicKeyColumn(key string, values [][]byte) *dynamicKeyColumn {
+	return &dynamicKeyColumn{ColumnJSONBytes: column.NewColumnJSONBytes(key, values)}
+}
+
+// Get 返回该行在此键上的原始JSON值
+func (c *dynamicKeyColumn) Get(idx int) (any, error) {
+	value, err := c.ColumnJSONBytes.Value(idx)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(value), nil
+}
+
+// Slice 返回部分行组成的新列，用于客户端拆分超大请求
+func (c *dynamicKeyColumn) Slice(start, end int) column.Column {
+	return &dynamicKeyColumn{ColumnJSONBytes: c.ColumnJSONBytes.Slice(start, end).(*column.ColumnJSONBytes)}
+}
@@ -0,0 +1,614 @@
+package client
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/row"
+	"github.com/pkg/errors"
+)
+
+// BatchWriterOptions 定义批量写入器的配置选项
+type BatchWriterOptions struct {
+	MaxRows        int           // 单批最大行数，缓冲区达到该行数时立即刷新
+	MaxBytes       int           // 单批估算的最大字节数，缓冲区达到该大小时立即刷新
+	FlushInterval  time.Duration // 定时刷新间隔，缓冲区中的数据最多等待该时间后写入
+	MaxPendingRows int           // 已提交但未完成写入的行数上限，超过时Write阻塞（背压）
+	Concurrency    int           // 并发写入的批次数
+	MaxRetries     int           // 可重试错误的最大重试次数，0表示不重试；写入器的重试代替客户端对这些Insert的重试
+	RetryBackoff   time.Duration // 重试的初始退避时间，每次重试翻倍
+	WriteTimeout   time.Duration // 单次Insert调用的超时时间，0表示不设超时
+
+	// AllowAutoIDInsertRetry 是否重试自动生成主键的集合的批次，重试可能写入重复数据
+	AllowAutoIDInsertRetry bool
+
+	// OnResult 每行写入完成后的回调，可选；在写入goroutine中调用，不应阻塞
+	OnResult func(result WriteResult)
+}
+
+// DefaultBatchWriterOptions 返回批量写入器的默认配置
+func DefaultBatchWriterOptions() *BatchWriterOptions {
+	return &BatchWriterOptions{
+		MaxRows:        1000,
+		MaxBytes:       4 << 20, // 4MB
+		FlushInterval:  time.Second,
+		MaxPendingRows: 10000,
+		Concurrency:    1,
+		MaxRetries:     3,
+		RetryBackoff:   100 * time.Millisecond,
+		WriteTimeout:   30 * time.Second,
+	}
+}
+
+// BatchWriterOption 定义批量写入器配置选项函数类型
+type BatchWriterOption func(*BatchWriterOptions)
+
+// WithBatchLimits 设置单批的最大行数与估算字节数
+func WithBatchLimits(maxRows, maxBytes int) BatchWriterOption {
+	return func(o *BatchWriterOptions) {
+		o.MaxRows = maxRows
+		o.MaxBytes = maxBytes
+	}
+}
+
+// WithFlushInterval 设置定时刷新间隔
+func WithFlushInterval(interval time.Duration) BatchWriterOption {
+	return func(o *BatchWriterOptions) {
+		o.FlushInterval = interval
+	}
+}
+
+// WithMaxPendingRows 设置背压阈值
+func WithMaxPendingRows(maxPendingRows int) BatchWriterOption {
+	return func(o *BatchWriterOptions) {
+		o.MaxPendingRows = maxPendingRows
+	}
+}
+
+// WithWriteConcurrency 设置并发写入的批次数
+func WithWriteConcurrency(concurrency int) BatchWriterOption {
+	return func(o *BatchWriterOptions) {
+		o.Concurrency = concurrency
+	}
+}
+
+// WithWriteRetry 设置失败批次的重试次数与初始退避时间
+func WithWriteRetry(maxRetries int, backoff time.Duration) BatchWriterOption {
+	return func(o *BatchWriterOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryBackoff = backoff
+	}
+}
+
+// WithWriteAutoIDRetry 设置是否重试自动生成主键的集合的批次
+// allow: 超时的Insert可能已在服务端写入，重试会写入重复数据，默认false
+func WithWriteAutoIDRetry(allow bool) BatchWriterOption {
+	return func(o *BatchWriterOptions) {
+		o.AllowAutoIDInsertRetry = allow
+	}
+}
+
+// WithWriteTimeout 设置单次Insert调用的超时时间
+func WithWriteTimeout(timeout time.Duration) BatchWriterOption {
+	return func(o *BatchWriterOptions) {
+		o.WriteTimeout = timeout
+	}
+}
+
+// WithResultCallback 设置每行写入完成后的回调
+func WithResultCallback(fn func(result WriteResult)) BatchWriterOption {
+	return func(o *BatchWriterOptions) {
+		o.OnResult = fn
+	}
+}
+
+// WriteResult 单行写入结果
+type WriteResult struct {
+	Collection string // 集合名称
+	Partition  string // 分区名称
+	Row        any    // 原始行数据
+	ID         any    // 写入后的主键，int64或string；失败时为nil
+	Err        error  // 写入错误，成功时为nil
+}
+
+// WriteFuture 单行写入的异步结果
+type WriteFuture struct {
+	done   chan struct{}
+	result WriteResult
+}
+
+// Done 返回写入完成时关闭的通道
+func (f *WriteFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait 等待写入完成
+// ctx: 上下文，用于控制等待时长；取消等待不会取消写入
+// 返回值: (写入后的主键, 错误信息)
+func (f *WriteFuture) Wait(ctx context.Context) (any, error) {
+	select {
+	case <-f.done:
+		return f.result.ID, f.result.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Result 返回写入结果，未完成时返回false
+func (f *WriteFuture) Result() (WriteResult, bool) {
+	select {
+	case <-f.done:
+		return f.result, true
+	default:
+		return WriteResult{}, false
+	}
+}
+
+// batchKey 缓冲区的键，每个集合/分区一个缓冲区
+type batchKey struct {
+	collection string
+	partition  string
+}
+
+// writeBatch 待写入的一批行
+type writeBatch struct {
+	key     batchKey
+	rows    []any
+	futures []*WriteFuture
+	bytes   int
+	done    chan struct{}
+}
+
+// BatchWriter 异步批量写入器
+// 按集合/分区累积单行数据，达到行数、大小或时间间隔后批量调用Insert，
+// 失败的批次按配置重试，每行的结果通过WriteFuture或回调返回
+type BatchWriter struct {
+	cli     Client
+	options *BatchWriterOptions
+
+	mu       sync.Mutex
+	buffers  map[batchKey]*writeBatch
+	inflight map[*writeBatch]struct{}
+	schemas  map[string]*entity.Schema
+	closed   bool
+
+	slots   chan struct{}
+	queue   chan *writeBatch
+	stop    chan struct{}
+	workers sync.WaitGroup
+}
+
+// NewBatchWriter 创建批量写入器
+// cli: Milvus客户端
+// opts: 配置选项列表
+func NewBatchWriter(cli Client, opts ...BatchWriterOption) *BatchWriter {
+	options := DefaultBatchWriterOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	defaults := DefaultBatchWriterOptions()
+	if options.MaxRows <= 0 {
+		options.MaxRows = defaults.MaxRows
+	}
+	if options.MaxPendingRows < options.MaxRows {
+		options.MaxPendingRows = options.MaxRows
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = defaults.Concurrency
+	}
+
+	w := &BatchWriter{
+		cli:      cli,
+		options:  options,
+		buffers:  make(map[batchKey]*writeBatch),
+		inflight: make(map[*writeBatch]struct{}),
+		schemas:  make(map[string]*entity.Schema),
+		slots:    make(chan struct{}, options.MaxPendingRows),
+		queue:    make(chan *writeBatch, options.Concurrency),
+		stop:     make(chan struct{}),
+	}
+
+	for i := 0; i < options.Concurrency; i++ {
+		w.workers.Add(1)
+		go w.worker()
+	}
+	if options.FlushInterval > 0 {
+		w.workers.Add(1)
+		go w.ticker()
+	}
+	return w
+}
+
+// Write 提交单行数据，数据会在后台批量写入
+// 待写入行数达到上限时阻塞，直到有空间或ctx结束
+// ctx: 上下文，用于控制背压等待时长
+// collectionName: 集合名称，例如"my_collection"
+// partitionName: 分区名称，空字符串表示默认分区
+// row: 行数据，map[string]any或带milvus标签的结构体，需包含除自动生成主键外的所有字段，例如map[string]any{"id": 1, "vector": []float32{...}}；
+// map行可以省略可空字段与有默认值的字段，写入时分别补全为null与默认值
+// 返回值: (写入结果, 错误信息)；行数据与集合模式不匹配时立即返回错误
+func (w *BatchWriter) Write(ctx context.Context, collectionName string, partitionName string, row any) (*WriteFuture, error) {
+	schema, err := w.schema(ctx, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	size, err := estimateRowSize(row, schema)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid row")
+	}
+
+	select {
+	case w.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	future := &WriteFuture{done: make(chan struct{})}
+	key := batchKey{collection: collectionName, partition: partitionName}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		<-w.slots
+		return nil, ErrClosed
+	}
+	batch, ok := w.buffers[key]
+	if !ok {
+		batch = &writeBatch{key: key}
+		w.buffers[key] = batch
+	}
+	batch.rows = append(batch.rows, row)
+	batch.futures = append(batch.futures, future)
+	batch.bytes += size
+
+	var full *writeBatch
+	if len(batch.rows) >= w.options.MaxRows || (w.options.MaxBytes > 0 && batch.bytes >= w.options.MaxBytes) {
+		full = w.detach(key)
+	}
+	w.mu.Unlock()
+
+	if full != nil {
+		w.enqueue(full)
+	}
+	return future, nil
+}
+
+// Flush 立即写入所有缓冲数据，并等待此前提交的所有数据写入完成
+// ctx: 上下文，用于控制等待时长
+func (w *BatchWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	batches := w.detachAll()
+	waiting := make([]*writeBatch, 0, len(w.inflight))
+	for batch := range w.inflight {
+		waiting = append(waiting, batch)
+	}
+	w.mu.Unlock()
+
+	for _, batch := range batches {
+		w.enqueue(batch)
+	}
+	for _, batch := range append(waiting, batches...) {
+		select {
+		case <-batch.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close 写入所有缓冲数据并停止写入器，之后的Write会返回错误
+// ctx: 上下文，用于控制等待时长；超时后未完成的写入仍会在后台继续
+func (w *BatchWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	err := w.Flush(ctx)
+	close(w.stop)
+	if err != nil {
+		return err
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		w.workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// detach 取出缓冲区并登记为进行中，调用方需持有锁
+func (w *BatchWriter) detach(key batchKey) *writeBatch {
+	batch := w.buffers[key]
+	delete(w.buffers, key)
+	batch.done = make(chan struct{})
+	w.inflight[batch] = struct{}{}
+	return batch
+}
+
+// detachAll 取出所有非空缓冲区，调用方需持有锁
+func (w *BatchWriter) detachAll() []*writeBatch {
+	batches := make([]*writeBatch, 0, len(w.buffers))
+	for key := range w.buffers {
+		batches = append(batches, w.detach(key))
+	}
+	return batches
+}
+
+// ticker 定时刷新缓冲区
+func (w *BatchWriter) ticker() {
+	defer w.workers.Done()
+
+	t := time.NewTicker(w.options.FlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			w.mu.Lock()
+			batches := w.detachAll()
+			w.mu.Unlock()
+			for _, batch := range batches {
+				w.enqueue(batch)
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// enqueue 将批次交给写入goroutine，写入器已停止时直接写入
+func (w *BatchWriter) enqueue(batch *writeBatch) {
+	select {
+	case w.queue <- batch:
+	case <-w.stop:
+		w.write(batch)
+	}
+}
+
+// worker 消费待写入批次
+func (w *BatchWriter) worker() {
+	defer w.workers.Done()
+	for {
+		select {
+		case batch := <-w.queue:
+			w.write(batch)
+		case <-w.stop:
+			// 处理停止前已入队的批次
+			for {
+				select {
+				case batch := <-w.queue:
+					w.write(batch)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write 写入一个批次并分发每行结果
+// 部分写入失败时，已写入的行返回主键，其余行返回错误
+func (w *BatchWriter) write(batch *writeBatch) {
+	ids, err := w.insert(batch)
+	for i, future := range batch.futures {
+		future.result = WriteResult{
+			Collection: batch.key.collection,
+			Partition:  batch.key.partition,
+			Row:        batch.rows[i],
+		}
+		if ids != nil && i < ids.Len() {
+			future.result.ID, _ = ids.Get(i)
+		} else {
+			future.result.Err = err
+		}
+		close(future.done)
+		if w.options.OnResult != nil {
+			w.options.OnResult(future.result)
+		}
+		<-w.slots
+	}
+
+	w.mu.Lock()
+	delete(w.inflight, batch)
+	w.mu.Unlock()
+	close(batch.done)
+}
+
+// insert 将批次转换为列数据并写入，可重试的错误按指数退避重试
+// 客户端拆分请求后部分写入失败时返回已写入行的主键，重试只发送之后的行，避免重复写入；
+// 自动生成主键的集合默认不重试；写入器停止后不再重试
+// 返回值: (已写入行的主键，按行顺序, 错误信息)
+func (w *BatchWriter) insert(batch *writeBatch) (column.Column, error) {
+	schema, err := w.schema(context.Background(), batch.key.collection)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]any, len(batch.rows))
+	for i, r := range batch.rows {
+		rows[i] = completeRow(r, schema)
+	}
+	columns, err := row.AnyToColumns(rows, schema)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert rows")
+	}
+	maxRetries := w.options.MaxRetries
+	if !w.options.AllowAutoIDInsertRetry && schemaAutoID(schema) {
+		maxRetries = 0
+	}
+
+	var written column.Column
+	dynamic := dynamicColumns(columns)
+	backoff := w.options.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		pending := columns
+		if written != nil {
+			pending = sliceColumns(columns, dynamic, written.Len(), len(rows))
+		}
+
+		// 由写入器负责重试，避免与客户端的重试策略叠加
+		ctx, cancel := withoutRetry(context.Background()), context.CancelFunc(func() {})
+		if w.options.WriteTimeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, w.options.WriteTimeout)
+		}
+		ids, err := w.cli.Insert(ctx, batch.key.collection, batch.key.partition, pending...)
+		cancel()
+		if ids != nil && ids.Len() > 0 {
+			if written == nil {
+				written = ids
+			} else if mergeErr := appendColumn(written, ids); mergeErr != nil {
+				return written, errors.Wrap(mergeErr, "failed to merge inserted ids")
+			}
+		}
+		if err != nil {
+			w.invalidateSchema(batch.key.collection, err)
+		}
+		if err == nil || attempt >= maxRetries || !IsRetryable(err) {
+			return written, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-w.stop:
+			timer.Stop()
+			return written, err
+		}
+		backoff *= 2
+	}
+}
+
+// schemaAutoID 判断集合是否自动生成主键
+func schemaAutoID(schema *entity.Schema) bool {
+	if schema.AutoID {
+		return true
+	}
+	for _, field := range schema.Fields {
+		if field.PrimaryKey && field.AutoID {
+			return true
+		}
+	}
+	return false
+}
+
+// schema 获取并缓存集合模式
+func (w *BatchWriter) schema(ctx context.Context, collectionName string) (*entity.Schema, error) {
+	w.mu.Lock()
+	schema, ok := w.schemas[collectionName]
+	w.mu.Unlock()
+	if ok {
+		return schema, nil
+	}
+
+	coll, err := w.cli.DescribeCollection(ctx, collectionName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe collection %s", collectionName)
+	}
+
+	w.mu.Lock()
+	w.schemas[collectionName] = coll.Schema
+	w.mu.Unlock()
+	return coll.Schema, nil
+}
+
+// invalidateSchema 错误表明集合已不存在或模式已变化时删除缓存的集合模式，之后的写入重新获取
+func (w *BatchWriter) invalidateSchema(collectionName string, err error) {
+	if !errors.Is(err, ErrCollectionNotFound) && !errors.Is(err, ErrSchemaMismatch) {
+		return
+	}
+	w.mu.Lock()
+	delete(w.schemas, collectionName)
+	w.mu.Unlock()
+}
+
+// completeRow 为map行补全缺失的可空字段与有默认值的字段，可空字段补全为null，有默认值的字段补全为默认值
+// 同一批次中各行缺失的字段不同时，补全后转换的各列行数才能一致；返回副本，不修改原始行
+func completeRow(r any, schema *entity.Schema) any {
+	values, ok := r.(map[string]any)
+	if !ok {
+		return r
+	}
+	var completed map[string]any
+	for _, field := range schema.Fields {
+		if _, present := values[field.Name]; present || (!field.Nullable && field.DefaultValue == nil) {
+			continue
+		}
+		value := fieldDefault(field)
+		if value == nil && !field.Nullable {
+			continue
+		}
+		if completed == nil {
+			completed = maps.Clone(values)
+		}
+		completed[field.Name] = value
+	}
+	if completed == nil {
+		return r
+	}
+	return completed
+}
+
+// fieldDefault 将字段的默认值转换为对应列类型的值，没有默认值或类型不支持时返回nil
+func fieldDefault(field *entity.Field) any {
+	value := field.DefaultValue
+	if value == nil {
+		return nil
+	}
+	switch field.DataType {
+	case entity.FieldTypeBool:
+		return value.GetBoolData()
+	case entity.FieldTypeInt8:
+		return int8(value.GetIntData())
+	case entity.FieldTypeInt16:
+		return int16(value.GetIntData())
+	case entity.FieldTypeInt32:
+		return value.GetIntData()
+	case entity.FieldTypeInt64:
+		return value.GetLongData()
+	case entity.FieldTypeFloat:
+		return value.GetFloatData()
+	case entity.FieldTypeDouble:
+		return value.GetDoubleData()
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		return value.GetStringData()
+	case entity.FieldTypeJSON:
+		return value.GetBytesData()
+	}
+	return nil
+}
+
+// estimateRowSize 将单行转换为列数据以校验行数据，并估算其序列化后的字节数
+// 缺失的可空字段与有默认值的字段先按completeRow补全
+func estimateRowSize(r any, schema *entity.Schema) (int, error) {
+	columns, err := row.AnyToColumns([]any{completeRow(r, schema)}, schema)
+	if err != nil {
+		return 0, err
+	}
+	size := 0
+	present := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		present[col.Name()] = true
+		size += columnSize(col)
+	}
+	// 缺失字段不会生成列，同一批次中各列行数将不一致
+	for _, field := range schema.Fields {
+		if (field.PrimaryKey && field.AutoID) || field.IsDynamic || present[field.Name] {
+			continue
+		}
+		return 0, errors.Errorf("missing field %s", field.Name)
+	}
+	return size, nil
+}
@@ -0,0 +1,442 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// fakeInsertClient 只实现DescribeCollection与Insert的测试客户端
+type fakeInsertClient struct {
+	Client
+
+	mu       sync.Mutex
+	batches  []int
+	calls    int
+	failures int   // 前N次Insert返回错误
+	err      error // 失败时返回的错误，nil表示可重试的服务不可用错误
+	autoID   bool  // 集合是否自动生成主键
+	optional bool  // 集合是否包含可空字段note与默认值为7的字段score
+	block    chan struct{}
+
+	describes int             // DescribeCollection调用次数
+	columns   []column.Column // 最近一次Insert的列数据
+}
+
+func (c *fakeInsertClient) DescribeCollection(ctx context.Context, collectionName string) (*entity.Collection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.describes++
+	schema := &entity.Schema{
+		CollectionName: collectionName,
+		Fields: []*entity.Field{
+			{Name: "id", DataType: entity.FieldTypeInt64, PrimaryKey: true, AutoID: c.autoID},
+			{Name: "vector", DataType: entity.FieldTypeFloatVector, TypeParams: map[string]string{"dim": "2"}},
+		},
+	}
+	if c.optional {
+		schema.Fields = append(schema.Fields,
+			&entity.Field{Name: "note", DataType: entity.FieldTypeVarChar, Nullable: true, TypeParams: map[string]string{"max_length": "64"}},
+			&entity.Field{Name: "score", DataType: entity.FieldTypeInt64, DefaultValue: &schemapb.ValueField{Data: &schemapb.ValueField_LongData{LongData: 7}}})
+	}
+	return &entity.Collection{Name: collectionName, Schema: schema}, nil
+}
+
+func (c *fakeInsertClient) Insert(ctx context.Context, collectionName string, partitionName string, columns ...column.Column) (column.Column, error) {
+	if c.block != nil {
+		<-c.block
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	c.columns = columns
+	if c.failures > 0 {
+		c.failures--
+		if c.err != nil {
+			return nil, c.err
+		}
+		return nil, convertError("Insert", merr.ErrServiceUnavailable)
+	}
+	if c.autoID {
+		c.batches = append(c.batches, columns[0].Len())
+		return column.NewColumnInt64("id", make([]int64, columns[0].Len())), nil
+	}
+	for _, col := range columns {
+		if col.Name() == "id" {
+			c.batches = append(c.batches, col.Len())
+			return col, nil
+		}
+	}
+	return nil, errors.New("missing primary key")
+}
+
+func (c *fakeInsertClient) insertCalls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func (c *fakeInsertClient) describeCalls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.describes
+}
+
+// insertedColumn 返回最近一次Insert中指定名称的列
+func (c *fakeInsertClient) insertedColumn(name string) column.Column {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, col := range c.columns {
+		if col.Name() == name {
+			return col
+		}
+	}
+	return nil
+}
+
+func (c *fakeInsertClient) batchSizes() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int(nil), c.batches...)
+}
+
+func testRow(id int64) map[string]any {
+	return map[string]any{"id": id, "vector": []float32{float32(id), 0}}
+}
+
+// insertServer 实现DescribeCollection与Insert的Milvus服务，记录写入的主键，可以指定某次Insert失败
+type insertServer struct {
+	milvuspb.UnimplementedMilvusServiceServer
+
+	mu     sync.Mutex
+	calls  int
+	failAt int   // 第几次Insert返回错误，从1开始，0表示不失败
+	err    error // 失败时返回的错误
+	ids    []int64
+}
+
+// DescribeCollection 返回主键id与2维向量字段的集合模式
+func (s *insertServer) DescribeCollection(ctx context.Context, req *milvuspb.DescribeCollectionRequest) (*milvuspb.DescribeCollectionResponse, error) {
+	return &milvuspb.DescribeCollectionResponse{
+		Status:         merr.Success(),
+		CollectionName: req.GetCollectionName(),
+		CollectionID:   1,
+		Schema: &schemapb.CollectionSchema{
+			Name: req.GetCollectionName(),
+			Fields: []*schemapb.FieldSchema{
+				{FieldID: 100, Name: "id", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+				{FieldID: 101, Name: "vector", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: "dim", Value: "2"}}},
+			},
+		},
+	}, nil
+}
+
+// Insert 记录写入的主键并返回，第failAt次调用返回错误
+func (s *insertServer) Insert(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls == s.failAt {
+		return &milvuspb.MutationResult{Status: merr.Status(s.err)}, nil
+	}
+	var ids []int64
+	for _, fd := range req.GetFieldsData() {
+		if fd.GetFieldName() == "id" {
+			ids = fd.GetScalars().GetLongData().GetData()
+		}
+	}
+	s.ids = append(s.ids, ids...)
+	return &milvuspb.MutationResult{
+		Status:    merr.Success(),
+		IDs:       &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+		InsertCnt: int64(len(ids)),
+	}, nil
+}
+
+// inserted 返回写入的所有主键
+func (s *insertServer) inserted() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int64(nil), s.ids...)
+}
+
+// TestBatchWriterPartialInsert 测试客户端拆分请求后部分写入失败时，只重试之后的行并按行返回结果
+func TestBatchWriterPartialInsert(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// start 启动第failAt次Insert返回err的服务，创建每次请求不超过约3行的客户端
+	start := func(t *testing.T, failAt int, err error) (*insertServer, Client) {
+		srv := &insertServer{failAt: failAt, err: err}
+		addr := testserver.Start(t, testserver.WithRegister(func(server *grpc.Server) {
+			milvuspb.RegisterMilvusServiceServer(server, srv)
+		}))
+		cli, cliErr := NewWithOptions(ctx, WithAddress(addr), WithDisableConn(true), WithRequestLimits(40, 0))
+		require.NoError(t, cliErr)
+		t.Cleanup(func() { _ = cli.Close() })
+		return srv, cli
+	}
+
+	// writeAll 写入主键0到9的行并等待结果
+	writeAll := func(t *testing.T, w *BatchWriter) []WriteResult {
+		futures := make([]*WriteFuture, 10)
+		for i := range futures {
+			f, err := w.Write(ctx, "c1", "", testRow(int64(i)))
+			require.NoError(t, err)
+			futures[i] = f
+		}
+		require.NoError(t, w.Flush(ctx))
+		results := make([]WriteResult, len(futures))
+		for i, f := range futures {
+			result, ok := f.Result()
+			require.True(t, ok)
+			results[i] = result
+		}
+		return results
+	}
+
+	t.Run("重试时不重复写入已写入的分段", func(t *testing.T) {
+		srv, cli := start(t, 2, merr.WrapErrServiceNotReady("proxy", 1, "Initializing"))
+		w := NewBatchWriter(cli, WithBatchLimits(10, 0), WithFlushInterval(0), WithWriteRetry(3, time.Millisecond))
+		defer w.Close(ctx)
+
+		for i, result := range writeAll(t, w) {
+			require.NoError(t, result.Err)
+			assert.Equal(t, int64(i), result.ID)
+		}
+		assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, srv.inserted(), "每行只写入一次")
+	})
+
+	t.Run("不可重试时已写入的行返回成功", func(t *testing.T) {
+		srv, cli := start(t, 2, merr.WrapErrParameterInvalidMsg("bad row"))
+		w := NewBatchWriter(cli, WithBatchLimits(10, 0), WithFlushInterval(0), WithWriteRetry(3, time.Millisecond))
+		defer w.Close(ctx)
+
+		results := writeAll(t, w)
+		written := len(srv.inserted())
+		require.Greater(t, written, 0)
+		require.Less(t, written, 10)
+		for i, result := range results {
+			if i < written {
+				assert.NoError(t, result.Err)
+				assert.Equal(t, int64(i), result.ID)
+			} else {
+				assert.ErrorIs(t, result.Err, ErrInvalidParameter)
+				assert.Nil(t, result.ID)
+			}
+		}
+	})
+}
+
+// TestBatchWriter 测试批量写入器
+func TestBatchWriter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("按行数刷新", func(t *testing.T) {
+		cli := &fakeInsertClient{}
+		w := NewBatchWriter(cli, WithBatchLimits(2, 0), WithFlushInterval(0))
+
+		futures := make([]*WriteFuture, 0, 5)
+		for i := int64(1); i <= 5; i++ {
+			f, err := w.Write(ctx, "c1", "", testRow(i))
+			require.NoError(t, err)
+			futures = append(futures, f)
+		}
+		require.NoError(t, w.Close(ctx))
+
+		assert.Equal(t, []int{2, 2, 1}, cli.batchSizes())
+		for i, f := range futures {
+			id, err := f.Wait(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int64(i+1), id)
+		}
+	})
+
+	t.Run("按时间间隔刷新", func(t *testing.T) {
+		cli := &fakeInsertClient{}
+		w := NewBatchWriter(cli, WithFlushInterval(20*time.Millisecond))
+		defer w.Close(ctx)
+
+		f, err := w.Write(ctx, "c1", "", testRow(1))
+		require.NoError(t, err)
+		id, err := f.Wait(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+
+	t.Run("失败重试与回调", func(t *testing.T) {
+		cli := &fakeInsertClient{failures: 2}
+		var mu sync.Mutex
+		var results []WriteResult
+		w := NewBatchWriter(cli,
+			WithFlushInterval(0),
+			WithWriteRetry(1, time.Millisecond),
+			WithResultCallback(func(r WriteResult) {
+				mu.Lock()
+				results = append(results, r)
+				mu.Unlock()
+			}),
+		)
+
+		first, err := w.Write(ctx, "c1", "p1", testRow(1))
+		require.NoError(t, err)
+		require.NoError(t, w.Flush(ctx))
+		_, err = first.Wait(ctx)
+		assert.Error(t, err, "重试次数用尽后应返回错误")
+
+		second, err := w.Write(ctx, "c1", "p1", testRow(2))
+		require.NoError(t, err)
+		require.NoError(t, w.Close(ctx))
+		id, err := second.Wait(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), id)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, results, 2)
+		assert.Equal(t, "p1", results[0].Partition)
+		assert.Error(t, results[0].Err)
+		assert.NoError(t, results[1].Err)
+	})
+
+	t.Run("不可重试的错误不重试", func(t *testing.T) {
+		cli := &fakeInsertClient{failures: 1, err: convertError("Insert", merr.WrapErrParameterInvalidMsg("dim mismatch"))}
+		w := NewBatchWriter(cli, WithFlushInterval(0), WithWriteRetry(3, time.Millisecond))
+
+		f, err := w.Write(ctx, "c1", "", testRow(1))
+		require.NoError(t, err)
+		require.NoError(t, w.Close(ctx))
+		_, err = f.Wait(ctx)
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+		assert.Equal(t, 1, cli.insertCalls())
+	})
+
+	t.Run("自动生成主键的集合默认不重试", func(t *testing.T) {
+		autoIDRow := map[string]any{"vector": []float32{1, 0}}
+		cli := &fakeInsertClient{failures: 1, autoID: true}
+		w := NewBatchWriter(cli, WithFlushInterval(0), WithWriteRetry(3, time.Millisecond))
+		f, err := w.Write(ctx, "c1", "", autoIDRow)
+		require.NoError(t, err)
+		require.NoError(t, w.Close(ctx))
+		_, err = f.Wait(ctx)
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.Equal(t, 1, cli.insertCalls())
+
+		cli = &fakeInsertClient{failures: 1, autoID: true}
+		w = NewBatchWriter(cli, WithFlushInterval(0), WithWriteRetry(3, time.Millisecond), WithWriteAutoIDRetry(true))
+		f, err = w.Write(ctx, "c1", "", autoIDRow)
+		require.NoError(t, err)
+		require.NoError(t, w.Close(ctx))
+		_, err = f.Wait(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, cli.insertCalls())
+	})
+
+	t.Run("停止后不再重试", func(t *testing.T) {
+		cli := &fakeInsertClient{failures: 10}
+		w := NewBatchWriter(cli, WithFlushInterval(0), WithWriteRetry(5, time.Hour))
+		f, err := w.Write(ctx, "c1", "", testRow(1))
+		require.NoError(t, err)
+
+		closeCtx, closeCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer closeCancel()
+		assert.ErrorIs(t, w.Close(closeCtx), context.DeadlineExceeded, "退避等待中的批次未完成")
+		_, err = f.Wait(ctx)
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.Equal(t, 1, cli.insertCalls())
+	})
+
+	t.Run("行数据校验", func(t *testing.T) {
+		w := NewBatchWriter(&fakeInsertClient{}, WithFlushInterval(0))
+		defer w.Close(ctx)
+
+		_, err := w.Write(ctx, "c1", "", map[string]any{"id": int64(1)})
+		assert.Error(t, err)
+	})
+
+	t.Run("省略可空字段与有默认值的字段", func(t *testing.T) {
+		cli := &fakeInsertClient{optional: true}
+		w := NewBatchWriter(cli, WithBatchLimits(3, 0), WithFlushInterval(0))
+		defer w.Close(ctx)
+
+		full := testRow(2)
+		full["note"] = "hello"
+		full["score"] = int64(9)
+		for _, r := range []map[string]any{testRow(1), full, testRow(3)} {
+			_, err := w.Write(ctx, "c1", "", r)
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Flush(ctx))
+		assert.NotContains(t, testRow(1), "note", "不修改原始行")
+
+		note := cli.insertedColumn("note")
+		require.NotNil(t, note)
+		require.Equal(t, 3, note.Len())
+		for i, want := range []bool{true, false, true} {
+			isNull, err := note.IsNull(i)
+			require.NoError(t, err)
+			assert.Equal(t, want, isNull)
+		}
+		value, err := note.GetAsString(1)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", value)
+
+		score := cli.insertedColumn("score")
+		require.NotNil(t, score)
+		for i, want := range []int64{7, 9, 7} {
+			value, err := score.GetAsInt64(i)
+			require.NoError(t, err)
+			assert.Equal(t, want, value)
+		}
+	})
+
+	t.Run("集合不存在时重新获取模式", func(t *testing.T) {
+		cli := &fakeInsertClient{failures: 1, err: convertError("Insert", merr.WrapErrCollectionNotFound("c1"))}
+		w := NewBatchWriter(cli, WithBatchLimits(1, 0), WithFlushInterval(0))
+		defer w.Close(ctx)
+
+		f, err := w.Write(ctx, "c1", "", testRow(1))
+		require.NoError(t, err)
+		_, err = f.Wait(ctx)
+		assert.ErrorIs(t, err, ErrCollectionNotFound)
+
+		f, err = w.Write(ctx, "c1", "", testRow(2))
+		require.NoError(t, err)
+		_, err = f.Wait(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, cli.describeCalls(), "缓存的模式失效后重新获取")
+	})
+
+	t.Run("背压", func(t *testing.T) {
+		cli := &fakeInsertClient{block: make(chan struct{})}
+		w := NewBatchWriter(cli, WithBatchLimits(1, 0), WithMaxPendingRows(1), WithFlushInterval(0))
+
+		_, err := w.Write(ctx, "c1", "", testRow(1))
+		require.NoError(t, err)
+
+		waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer waitCancel()
+		_, err = w.Write(waitCtx, "c1", "", testRow(2))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(cli.block)
+		require.NoError(t, w.Close(ctx))
+		_, err = w.Write(ctx, "c1", "", testRow(3))
+		assert.ErrorIs(t, err, ErrClosed, "关闭后写入应返回错误")
+	})
+}
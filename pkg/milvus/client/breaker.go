@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BreakerState 熔断器状态
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // 关闭：正常放行请求并统计错误率与慢请求比例
+	BreakerOpen                         // 打开：直接返回ErrCircuitOpen，不发送请求
+	BreakerHalfOpen                     // 半开：放行少量探测请求，成功后关闭，失败后重新打开
+)
+
+// String 返回熔断器状态名称
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker 熔断器配置，一个客户端的所有操作共享一个熔断器
+// 值为0的字段使用DefaultCircuitBreaker中的默认值，ErrorRate、SlowThreshold、SlowRate除外
+type CircuitBreaker struct {
+	Window           time.Duration // 统计窗口，窗口结束后重新统计
+	MinRequests      int           // 窗口内达到该请求数后才判断是否打开
+	ErrorRate        float64       // 失败请求比例阈值，0~1，0表示不按错误率打开
+	SlowThreshold    time.Duration // 耗时达到该值的请求计为慢请求，0表示不统计慢请求
+	SlowRate         float64       // 慢请求比例阈值，0~1，0表示不按延迟打开
+	OpenTimeout      time.Duration // 打开状态的持续时间，之后进入半开状态
+	HalfOpenRequests int           // 半开状态放行的探测请求数，全部成功后关闭
+
+	// IsFailure 判断错误是否计为失败，nil表示服务不可用与超时计为失败
+	// 调用方取消的请求不参与统计
+	IsFailure func(err error) bool
+
+	// OnStateChange 状态变化时调用，在熔断器锁外执行，不应阻塞
+	OnStateChange func(from, to BreakerState)
+}
+
+// DefaultCircuitBreaker 返回默认的熔断器配置
+// 10秒内至少20个请求且一半以上失败时打开，30秒后进入半开状态
+func DefaultCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{
+		Window:           10 * time.Second,
+		MinRequests:      20,
+		ErrorRate:        0.5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// isFailure 判断错误是否计为失败
+func (cb *CircuitBreaker) isFailure(err error) bool {
+	if cb.IsFailure != nil {
+		return cb.IsFailure(err)
+	}
+	return errors.Is(err, ErrUnavailable) || errors.Is(err, ErrTimeout)
+}
+
+// circuitBreaker 熔断器的运行状态
+type circuitBreaker struct {
+	config   CircuitBreaker
+	onChange []func(from, to BreakerState)
+	now      func() time.Time
+
+	mu          sync.Mutex
+	state       BreakerState
+	windowStart time.Time
+	requests    int // 当前窗口内的请求数
+	failures    int // 当前窗口内的失败请求数
+	slow        int // 当前窗口内的慢请求数
+	openedAt    time.Time
+	probes      int // 半开状态已放行的探测请求数
+	successes   int // 半开状态成功的探测请求数
+}
+
+// newCircuitBreaker 按配置创建熔断器，未配置的字段使用默认值
+// onChange: 内部的状态变化回调，例如记录日志与指标，在配置的OnStateChange之前调用
+func newCircuitBreaker(config *CircuitBreaker, onChange ...func(from, to BreakerState)) *circuitBreaker {
+	defaults := DefaultCircuitBreaker()
+	b := &circuitBreaker{config: *config, now: time.Now}
+	if b.config.Window <= 0 {
+		b.config.Window = defaults.Window
+	}
+	if b.config.MinRequests <= 0 {
+		b.config.MinRequests = defaults.MinRequests
+	}
+	if b.config.OpenTimeout <= 0 {
+		b.config.OpenTimeout = defaults.OpenTimeout
+	}
+	if b.config.HalfOpenRequests <= 0 {
+		b.config.HalfOpenRequests = defaults.HalfOpenRequests
+	}
+	b.onChange = append(b.onChange, onChange...)
+	if config.OnStateChange != nil {
+		b.onChange = append(b.onChange, config.OnStateChange)
+	}
+	b.windowStart = b.now()
+	return b
+}
+
+// setState 切换状态，返回在锁外通知状态变化的函数，调用时必须持有锁
+func (b *circuitBreaker) setState(to BreakerState, now time.Time) func() {
+	from := b.state
+	b.state = to
+	b.windowStart = now
+	b.requests, b.failures, b.slow = 0, 0, 0
+	b.probes, b.successes = 0, 0
+	if to == BreakerOpen {
+		b.openedAt = now
+	}
+	return func() {
+		for _, fn := range b.onChange {
+			fn(from, to)
+		}
+	}
+}
+
+// allow 判断是否放行请求，probe表示该请求是半开状态的探测请求
+func (b *circuitBreaker) allow() (probe bool, err error) {
+	notify := func() {}
+	defer func() { notify() }()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case BreakerOpen:
+		if now.Sub(b.openedAt) < b.config.OpenTimeout {
+			return false, errors.Errorf("circuit breaker is open, retry after %v", b.openedAt.Add(b.config.OpenTimeout).Sub(now).Round(time.Millisecond))
+		}
+		notify = b.setState(BreakerHalfOpen, now)
+		fallthrough
+	case BreakerHalfOpen:
+		if b.probes >= b.config.HalfOpenRequests {
+			return false, errors.New("circuit breaker is half-open, waiting for probe requests")
+		}
+		b.probes++
+		return true, nil
+	default:
+		if now.Sub(b.windowStart) >= b.config.Window {
+			b.windowStart = now
+			b.requests, b.failures, b.slow = 0, 0, 0
+		}
+		return false, nil
+	}
+}
+
+// record 记录请求结果并按阈值切换状态
+func (b *circuitBreaker) record(probe bool, err error, elapsed time.Duration) {
+	notify := func() {}
+	defer func() { notify() }()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	ignored := errors.Is(err, context.Canceled)
+	failed := err != nil && !ignored && b.config.isFailure(err)
+	slow := b.config.SlowThreshold > 0 && elapsed >= b.config.SlowThreshold
+
+	if probe {
+		if b.state != BreakerHalfOpen {
+			return
+		}
+		switch {
+		case ignored:
+			b.probes--
+		case failed || slow:
+			notify = b.setState(BreakerOpen, now)
+		default:
+			b.successes++
+			if b.successes >= b.config.HalfOpenRequests {
+				notify = b.setState(BreakerClosed, now)
+			}
+		}
+		return
+	}
+
+	// 打开前已发出的请求不参与统计
+	if b.state != BreakerClosed || ignored {
+		return
+	}
+	if now.Sub(b.windowStart) >= b.config.Window {
+		b.windowStart = now
+		b.requests, b.failures, b.slow = 0, 0, 0
+	}
+	b.requests++
+	if failed {
+		b.failures++
+	}
+	if slow {
+		b.slow++
+	}
+	if b.requests < b.config.MinRequests {
+		return
+	}
+	requests := float64(b.requests)
+	if (b.config.ErrorRate > 0 && float64(b.failures)/requests >= b.config.ErrorRate) ||
+		(b.config.SlowRate > 0 && float64(b.slow)/requests >= b.config.SlowRate) {
+		notify = b.setState(BreakerOpen, now)
+	}
+}
+
+// interceptor 返回熔断拦截器，熔断器打开时直接返回ErrCircuitOpen
+func (b *circuitBreaker) interceptor() Interceptor {
+	return func(ctx context.Context, info *OperationInfo, invoker Invoker) error {
+		probe, err := b.allow()
+		if err != nil {
+			return &Error{Op: info.Name, Kind: ErrCircuitOpen, Err: err}
+		}
+		start := time.Now()
+		err = invoker(ctx, info)
+		b.record(probe, err, time.Since(start))
+		return err
+	}
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestCircuitBreaker 测试熔断器
+func TestCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	unavailable := func(ctx context.Context) error { return status.Error(codes.Unavailable, "down") }
+	ok := func(ctx context.Context) error { return nil }
+
+	newClient := func(cb *CircuitBreaker) (*client, *time.Time, *[]BreakerState) {
+		now := time.Now()
+		var events []BreakerState
+		cb.OnStateChange = func(from, to BreakerState) { events = append(events, to) }
+		c := newRetryTestClient(WithRetryPolicy(OpRead, nil))
+		// 使用可控的时钟，便于测试状态切换
+		breaker := newCircuitBreaker(cb)
+		breaker.now = func() time.Time { return now }
+		breaker.windowStart = now
+		c.interceptor = breaker.interceptor()
+		return c, &now, &events
+	}
+	query := &OperationInfo{Name: "Query", Class: OpRead}
+
+	t.Run("错误率超过阈值后打开", func(t *testing.T) {
+		c, _, events := newClient(&CircuitBreaker{MinRequests: 4, ErrorRate: 0.5})
+		require.NoError(t, c.invoke(ctx, query, ok))
+		require.NoError(t, c.invoke(ctx, query, ok))
+		_ = c.invoke(ctx, query, unavailable)
+		assert.Empty(t, *events)
+		_ = c.invoke(ctx, query, unavailable)
+		assert.Equal(t, []BreakerState{BreakerOpen}, *events)
+
+		called := false
+		err := c.invoke(ctx, query, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.False(t, IsRetryable(err))
+		assert.False(t, called)
+	})
+
+	t.Run("业务错误不计为失败", func(t *testing.T) {
+		c, _, events := newClient(&CircuitBreaker{MinRequests: 2, ErrorRate: 0.5})
+		for i := 0; i < 5; i++ {
+			_ = c.invoke(ctx, query, func(ctx context.Context) error {
+				return status.Error(codes.InvalidArgument, "bad expr")
+			})
+			_ = c.invoke(ctx, query, func(ctx context.Context) error { return context.Canceled })
+		}
+		assert.Empty(t, *events)
+	})
+
+	t.Run("慢请求比例超过阈值后打开", func(t *testing.T) {
+		c, _, events := newClient(&CircuitBreaker{MinRequests: 2, SlowThreshold: 10 * time.Millisecond, SlowRate: 1})
+		slow := func(ctx context.Context) error {
+			time.Sleep(15 * time.Millisecond)
+			return nil
+		}
+		require.NoError(t, c.invoke(ctx, query, slow))
+		require.NoError(t, c.invoke(ctx, query, slow))
+		assert.Equal(t, []BreakerState{BreakerOpen}, *events)
+	})
+
+	t.Run("半开探测成功后关闭", func(t *testing.T) {
+		c, now, events := newClient(&CircuitBreaker{MinRequests: 1, ErrorRate: 1, OpenTimeout: time.Second, HalfOpenRequests: 1})
+		_ = c.invoke(ctx, query, unavailable)
+		assert.ErrorIs(t, c.invoke(ctx, query, ok), ErrCircuitOpen)
+
+		*now = now.Add(time.Second)
+		err := c.invoke(ctx, query, func(ctx context.Context) error {
+			// 探测请求执行期间其他请求仍然快速失败
+			assert.ErrorIs(t, c.invoke(ctx, query, ok), ErrCircuitOpen)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerClosed}, *events)
+		require.NoError(t, c.invoke(ctx, query, ok))
+	})
+
+	t.Run("半开探测失败后重新打开", func(t *testing.T) {
+		c, now, events := newClient(&CircuitBreaker{MinRequests: 1, ErrorRate: 1, OpenTimeout: time.Second})
+		_ = c.invoke(ctx, query, unavailable)
+		*now = now.Add(time.Second)
+		_ = c.invoke(ctx, query, unavailable)
+		assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen}, *events)
+		assert.ErrorIs(t, c.invoke(ctx, query, ok), ErrCircuitOpen)
+	})
+
+	t.Run("统计窗口结束后重新统计", func(t *testing.T) {
+		c, now, events := newClient(&CircuitBreaker{Window: time.Second, MinRequests: 2, ErrorRate: 1})
+		_ = c.invoke(ctx, query, unavailable)
+		*now = now.Add(time.Second)
+		_ = c.invoke(ctx, query, unavailable)
+		assert.Empty(t, *events)
+	})
+
+	t.Run("记录状态指标", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		c := newRetryTestClient(
+			WithMetrics(reg, "main"),
+			WithRetryPolicy(OpRead, nil),
+			WithCircuitBreaker(&CircuitBreaker{MinRequests: 1, ErrorRate: 1}),
+		)
+		m, err := newClientMetrics(reg, nil)
+		require.NoError(t, err)
+		assert.Equal(t, float64(BreakerClosed), testutil.ToFloat64(m.circuitState.WithLabelValues("main")))
+
+		_ = c.invoke(ctx, query, unavailable)
+		assert.Equal(t, float64(BreakerOpen), testutil.ToFloat64(m.circuitState.WithLabelValues("main")))
+		_ = c.invoke(ctx, query, ok)
+		assert.Equal(t, 1.0, testutil.ToFloat64(m.errors.WithLabelValues("main", "Query", "0", "circuit_open")))
+	})
+}
@@ -2,16 +2,23 @@ package client
 
 import (
 	"context"
+	"log/slog"
+	"strings"
 	"sync"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/client/v2/column"
 	"github.com/milvus-io/milvus/client/v2/entity"
 	"github.com/milvus-io/milvus/client/v2/index"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 )
 
 // Client 定义 Milvus 客户端接口
@@ -23,20 +30,24 @@ type Client interface {
 	CreateDatabase(ctx context.Context, dbName string) error
 	DropDatabase(ctx context.Context, dbName string) error
 	UseDatabase(ctx context.Context, dbName string) error
+	ListDatabases(ctx context.Context) ([]string, error)
 
 	// Collection 相关操作
 	CreateCollection(ctx context.Context, schema *entity.Schema, shardNum int32) error
+	CreateCollectionWithOptions(ctx context.Context, schema *entity.Schema, opts ...CollectionOption) error
 	DropCollection(ctx context.Context, collectionName string) error
 	HasCollection(ctx context.Context, collectionName string) (bool, error)
 	LoadCollection(ctx context.Context, collectionName string) error
 	ReleaseCollection(ctx context.Context, collectionName string) error
 	GetCollectionStatistics(ctx context.Context, collectionName string) (map[string]string, error)
 	DescribeCollection(ctx context.Context, collectionName string) (*entity.Collection, error)
+	ListCollections(ctx context.Context) ([]string, error)
 
 	// 集合别名操作
 	CreateAlias(ctx context.Context, collectionName string, alias string) error
 	DropAlias(ctx context.Context, alias string) error
 	AlterAlias(ctx context.Context, collectionName string, alias string) error
+	ListAliases(ctx context.Context, collectionName string) ([]string, error)
 
 	// 分区相关操作
 	CreatePartition(ctx context.Context, collectionName string, partitionName string) error
@@ -44,29 +55,43 @@ type Client interface {
 	HasPartition(ctx context.Context, collectionName string, partitionName string) (bool, error)
 	LoadPartitions(ctx context.Context, collectionName string, partitionNames []string) error
 	ReleasePartitions(ctx context.Context, collectionName string, partitionNames []string) error
+	ListPartitions(ctx context.Context, collectionName string) ([]string, error)
 
 	// 索引相关操作
 	CreateIndex(ctx context.Context, collectionName string, fieldName string, idx index.Index) error
 	DropIndex(ctx context.Context, collectionName string, fieldName string) error
+	DescribeIndex(ctx context.Context, collectionName string, fieldName string) (index.Index, error)
 
 	// 数据操作
 	Insert(ctx context.Context, collectionName string, partitionName string, columns ...column.Column) (column.Column, error)
 	Delete(ctx context.Context, collectionName string, partitionName string, expr string) error
 	Search(ctx context.Context, collectionName string, partitionNames []string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, expr string, params map[string]string) ([]milvusclient.ResultSet, error)
 	Query(ctx context.Context, collectionName string, partitionNames []string, expr string, outputFields []string) ([]column.Column, error)
+	QueryWithLimit(ctx context.Context, collectionName string, partitionNames []string, expr string, outputFields []string, limit int) ([]column.Column, error)
 
 	// 批量操作
 	Compact(ctx context.Context, collectionName string) (int64, error)
 
+	// 健康检查
+	CheckHealth(ctx context.Context) error
+
 	// 关闭连接
 	Close() error
 }
 
 // client 实现 Client 接口
 type client struct {
-	cli    *milvusclient.Client
-	mu     sync.RWMutex
-	closed bool
+	cli         *milvusclient.Client
+	options     *Options
+	budget      *retryBudget
+	interceptor Interceptor
+	logger      *slog.Logger
+	connLog     *connLogger    // 记录连接事件，未配置日志时为nil
+	database    string         // 当前数据库，UseDatabase成功后更新
+	autoID      sync.Map       // 集合名称 -> 是否自动生成主键，用于判断Insert能否重试
+	inflight    sync.WaitGroup // 进行中的请求，Close等待其结束后再关闭连接
+	mu          sync.RWMutex   // 保护closed与database，请求执行期间不持有
+	closed      bool
 }
 
 // New 创建新的客户端实例
@@ -87,17 +112,49 @@ func NewWithOptions(ctx context.Context, opts ...Option) (Client, error) {
 	for _, opt := range opts {
 		opt(options)
 	}
+	if options.err != nil {
+		return nil, errors.Wrap(options.err, "invalid milvus client options")
+	}
+
+	// 配置多个地址时在地址之间故障转移
+	if len(options.Endpoints) > 0 {
+		f, err := newFailoverClient(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	c, err := newClient(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// newClient 使用应用选项后的配置连接options.Address
+func newClient(ctx context.Context, options *Options) (*client, error) {
+	// 在建立连接前组合拦截器，避免指标注册失败时遗留连接
+	interceptor, err := buildInterceptor(options)
+	if err != nil {
+		return nil, err
+	}
+
 	// 构建 milvusclient.ClientConfig
 	config := &milvusclient.ClientConfig{
 		Address:       options.Address,
 		Username:      options.Username,
 		Password:      options.Password,
 		DBName:        options.DBName,
-		EnableTLSAuth: options.EnableTLSAuth,
+		EnableTLSAuth: options.EnableTLSAuth || options.TLS != nil,
 		APIKey:        options.APIKey,
 		DisableConn:   options.DisableConn,
 	}
 
+	// 使用凭据提供者时由拦截器为每次请求设置凭据，不使用静态凭据
+	if options.CredentialProvider != nil {
+		config.Username, config.Password, config.APIKey = "", "", ""
+	}
+
 	// 设置重试配置
 	if options.MaxRetry > 0 || options.MaxRetryBackoff > 0 {
 		config.RetryRateLimit = &milvusclient.RetryRateLimitOption{
@@ -131,24 +188,99 @@ func NewWithOptions(ctx context.Context, opts ...Option) (Client, error) {
 	}
 	dialOptions = append(dialOptions, grpc.WithConnectParams(connectParams))
 
+	// 自定义TLS配置，位于SDK默认的传输凭据之后，覆盖默认配置
+	if options.TLS != nil {
+		tlsCfg, err := options.TLS.build(options.Address)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load TLS config")
+		}
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	}
+
 	// 添加消息大小配置
 	dialOptions = append(dialOptions, grpc.WithDefaultCallOptions(
 		grpc.MaxCallRecvMsgSize(options.MaxRecvMsgSize),
 	))
 
+	// 启用追踪时通过gRPC元数据传播追踪上下文
+	if options.TracerProvider != nil {
+		dialOptions = append(dialOptions,
+			grpc.WithChainUnaryInterceptor(tracingUnaryInterceptor(options.Propagator)),
+			grpc.WithChainStreamInterceptor(tracingStreamInterceptor(options.Propagator)),
+		)
+	}
+
+	// 为每次请求设置凭据，认证失败时刷新凭据并重试
+	if options.CredentialProvider != nil {
+		dialOptions = append(dialOptions,
+			grpc.WithChainUnaryInterceptor(credentialUnaryInterceptor(options.CredentialProvider)),
+			grpc.WithChainStreamInterceptor(credentialStreamInterceptor(options.CredentialProvider)),
+		)
+	}
+
+	// 添加用户提供的gRPC拦截器，位于SDK自带的重试与元数据拦截器之前
+	if len(options.UnaryInterceptors) > 0 {
+		dialOptions = append(dialOptions, grpc.WithChainUnaryInterceptor(options.UnaryInterceptors...))
+	}
+	if len(options.StreamInterceptors) > 0 {
+		dialOptions = append(dialOptions, grpc.WithChainStreamInterceptor(options.StreamInterceptors...))
+	}
+
+	// 配置日志时通过连接事件记录断开与重连
+	logger := newLogger(options)
+	var connLog *connLogger
+	if options.Logger != nil {
+		connLog = &connLogger{logger: logger, address: options.Address}
+		dialOptions = append(dialOptions, grpc.WithStatsHandler(connLog))
+	}
+
 	config.DialOptions = dialOptions
 
 	cli, err := milvusclient.New(ctx, config)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create milvus client")
+		err = errors.Wrap(convertError("Connect", err), "failed to create milvus client")
+		logger.ErrorContext(ctx, "failed to connect to milvus", slog.Any("options", options), slog.Any("error", err))
+		return nil, err
 	}
+	logger.InfoContext(ctx, "milvus client connected", slog.Any("options", options))
 
 	return &client{
-		cli:    cli,
-		closed: false,
+		cli:         cli,
+		options:     options,
+		budget:      newRetryBudget(options.RetryBudget),
+		interceptor: interceptor,
+		logger:      logger,
+		connLog:     connLog,
+		database:    options.DBName,
+		closed:      false,
 	}, nil
 }
 
+// begin 登记一次进行中的请求，客户端已关闭时返回false，返回true时请求结束后需要调用end
+// 只在检查关闭状态时持有锁，避免重试等待或限流等待阻塞其他请求与Close
+func (c *client) begin() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return false
+	}
+	c.inflight.Add(1)
+	return true
+}
+
+// end 结束一次进行中的请求
+func (c *client) end() {
+	c.inflight.Done()
+}
+
+// currentDatabase 返回当前数据库
+func (c *client) currentDatabase() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.database
+}
+
 // GetClient 获取原始 Milvus 客户端
 // 返回值: 原始Milvus客户端实例，如果客户端已关闭则返回nil
 func (c *client) GetClient() *milvusclient.Client {
@@ -167,32 +299,50 @@ func (c *client) GetClient() *milvusclient.Client {
 // schema: 集合模式定义，包含字段、索引等信息，例如包含id、vector、text字段的Schema
 // shardNum: 分片数量，用于数据分片存储，建议值为1-8
 func (c *client) CreateCollection(ctx context.Context, schema *entity.Schema, shardNum int32) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	return c.CreateCollectionWithOptions(ctx, schema, WithShardNum(shardNum))
+}
 
-	if c.closed {
-		return errors.New("client is closed")
+// CreateCollectionWithOptions 使用分片数量、一致性级别与集合属性等选项创建集合
+// ctx: 上下文，用于控制请求生命周期
+// schema: 集合模式定义
+// opts: 配置选项，例如WithShardNum(2)、WithConsistencyLevel(entity.ClStrong)，未设置的选项使用DefaultCollectionOptions
+func (c *client) CreateCollectionWithOptions(ctx context.Context, schema *entity.Schema, opts ...CollectionOption) error {
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
+	options := DefaultCollectionOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
 	option := milvusclient.NewCreateCollectionOption(schema.CollectionName, schema).
-		WithShardNum(shardNum)
+		WithShardNum(options.ShardNum).
+		WithConsistencyLevel(options.ConsistencyLevel)
+	for key, value := range options.Properties {
+		option.WithProperty(key, value)
+	}
 
-	return c.cli.CreateCollection(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "CreateCollection", Class: OpDDL, Collection: schema.CollectionName}, func(ctx context.Context) error {
+		return c.cli.CreateCollection(ctx, option)
+	})
 }
 
 // DropCollection 删除集合
 // ctx: 上下文，用于控制请求生命周期
 // collectionName: 要删除的集合名称，例如"my_collection"
 func (c *client) DropCollection(ctx context.Context, collectionName string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewDropCollectionOption(collectionName)
-	return c.cli.DropCollection(ctx, option)
+	err := c.invoke(ctx, &OperationInfo{Name: "DropCollection", Class: OpDDL, Collection: collectionName}, func(ctx context.Context) error {
+		return c.cli.DropCollection(ctx, option)
+	})
+	c.autoID.Delete(collectionName)
+	return err
 }
 
 // HasCollection 检查集合是否存在
@@ -200,49 +350,52 @@ func (c *client) DropCollection(ctx context.Context, collectionName string) erro
 // collectionName: 要检查的集合名称，例如"my_collection"
 // 返回值: (是否存在, 错误信息)
 func (c *client) HasCollection(ctx context.Context, collectionName string) (bool, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	if c.closed {
-		return false, errors.New("client is closed")
+	if !c.begin() {
+		return false, ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewHasCollectionOption(collectionName)
-	return c.cli.HasCollection(ctx, option)
+	var has bool
+	err := c.invoke(ctx, &OperationInfo{Name: "HasCollection", Class: OpRead, Collection: collectionName}, func(ctx context.Context) (err error) {
+		has, err = c.cli.HasCollection(ctx, option)
+		return err
+	})
+	return has, err
 }
 
 // LoadCollection 加载集合到内存
 // ctx: 上下文，用于控制请求生命周期
 // collectionName: 要加载的集合名称，例如"my_collection"
 func (c *client) LoadCollection(ctx context.Context, collectionName string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewLoadCollectionOption(collectionName)
-	task, err := c.cli.LoadCollection(ctx, option)
-	if err != nil {
-		return err
-	}
-	return task.Await(ctx)
+	return c.invoke(ctx, &OperationInfo{Name: "LoadCollection", Class: OpDDL, Collection: collectionName}, func(ctx context.Context) error {
+		task, err := c.cli.LoadCollection(ctx, option)
+		if err != nil {
+			return err
+		}
+		return task.Await(ctx)
+	})
 }
 
 // ReleaseCollection 从内存中释放集合
 // ctx: 上下文，用于控制请求生命周期
 // collectionName: 要释放的集合名称
 func (c *client) ReleaseCollection(ctx context.Context, collectionName string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewReleaseCollectionOption(collectionName)
-	return c.cli.ReleaseCollection(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "ReleaseCollection", Class: OpDDL, Collection: collectionName}, func(ctx context.Context) error {
+		return c.cli.ReleaseCollection(ctx, option)
+	})
 }
 
 // GetCollectionStatistics 获取集合统计信息
@@ -250,15 +403,18 @@ func (c *client) ReleaseCollection(ctx context.Context, collectionName string) e
 // collectionName: 集合名称
 // 返回值: (统计信息映射, 错误信息)
 func (c *client) GetCollectionStatistics(ctx context.Context, collectionName string) (map[string]string, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	if c.closed {
-		return nil, errors.New("client is closed")
+	if !c.begin() {
+		return nil, ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewGetCollectionStatsOption(collectionName)
-	return c.cli.GetCollectionStats(ctx, option)
+	var stats map[string]string
+	err := c.invoke(ctx, &OperationInfo{Name: "GetCollectionStatistics", Class: OpRead, Collection: collectionName}, func(ctx context.Context) (err error) {
+		stats, err = c.cli.GetCollectionStats(ctx, option)
+		return err
+	})
+	return stats, err
 }
 
 // CreatePartition 创建分区
@@ -266,15 +422,15 @@ func (c *client) GetCollectionStatistics(ctx context.Context, collectionName str
 // collectionName: 集合名称，例如"my_collection"
 // partitionName: 分区名称，例如"partition_1"
 func (c *client) CreatePartition(ctx context.Context, collectionName string, partitionName string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewCreatePartitionOption(collectionName, partitionName)
-	return c.cli.CreatePartition(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "CreatePartition", Class: OpDDL, Collection: collectionName, Args: map[string]any{"partition": partitionName}}, func(ctx context.Context) error {
+		return c.cli.CreatePartition(ctx, option)
+	})
 }
 
 // DropPartition 删除分区
@@ -282,15 +438,15 @@ func (c *client) CreatePartition(ctx context.Context, collectionName string, par
 // collectionName: 集合名称
 // partitionName: 要删除的分区名称
 func (c *client) DropPartition(ctx context.Context, collectionName string, partitionName string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewDropPartitionOption(collectionName, partitionName)
-	return c.cli.DropPartition(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "DropPartition", Class: OpDDL, Collection: collectionName, Args: map[string]any{"partition": partitionName}}, func(ctx context.Context) error {
+		return c.cli.DropPartition(ctx, option)
+	})
 }
 
 // HasPartition 检查分区是否存在
@@ -299,15 +455,18 @@ func (c *client) DropPartition(ctx context.Context, collectionName string, parti
 // partitionName: 要检查的分区名称
 // 返回值: (是否存在, 错误信息)
 func (c *client) HasPartition(ctx context.Context, collectionName string, partitionName string) (bool, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	if c.closed {
-		return false, errors.New("client is closed")
+	if !c.begin() {
+		return false, ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewHasPartitionOption(collectionName, partitionName)
-	return c.cli.HasPartition(ctx, option)
+	var has bool
+	err := c.invoke(ctx, &OperationInfo{Name: "HasPartition", Class: OpRead, Collection: collectionName, Args: map[string]any{"partition": partitionName}}, func(ctx context.Context) (err error) {
+		has, err = c.cli.HasPartition(ctx, option)
+		return err
+	})
+	return has, err
 }
 
 // LoadPartitions 加载分区到内存
@@ -315,19 +474,19 @@ func (c *client) HasPartition(ctx context.Context, collectionName string, partit
 // collectionName: 集合名称，例如"my_collection"
 // partitionNames: 要加载的分区名称列表，例如[]string{"partition_1", "partition_2"}
 func (c *client) LoadPartitions(ctx context.Context, collectionName string, partitionNames []string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewLoadPartitionsOption(collectionName, partitionNames...)
-	task, err := c.cli.LoadPartitions(ctx, option)
-	if err != nil {
-		return err
-	}
-	return task.Await(ctx)
+	return c.invoke(ctx, &OperationInfo{Name: "LoadPartitions", Class: OpDDL, Collection: collectionName, Args: map[string]any{"partitions": partitionNames}}, func(ctx context.Context) error {
+		task, err := c.cli.LoadPartitions(ctx, option)
+		if err != nil {
+			return err
+		}
+		return task.Await(ctx)
+	})
 }
 
 // ReleasePartitions 从内存中释放分区
@@ -335,15 +494,34 @@ func (c *client) LoadPartitions(ctx context.Context, collectionName string, part
 // collectionName: 集合名称
 // partitionNames: 要释放的分区名称列表
 func (c *client) ReleasePartitions(ctx context.Context, collectionName string, partitionNames []string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewReleasePartitionsOptions(collectionName, partitionNames...)
-	return c.cli.ReleasePartitions(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "ReleasePartitions", Class: OpDDL, Collection: collectionName, Args: map[string]any{"partitions": partitionNames}}, func(ctx context.Context) error {
+		return c.cli.ReleasePartitions(ctx, option)
+	})
+}
+
+// ListPartitions 列出集合的所有分区
+// ctx: 上下文，用于控制请求生命周期
+// collectionName: 集合名称，例如"my_collection"
+// 返回值: (分区名称列表，包含默认分区"_default", 错误信息)
+func (c *client) ListPartitions(ctx context.Context, collectionName string) ([]string, error) {
+	if !c.begin() {
+		return nil, ErrClosed
+	}
+	defer c.end()
+
+	option := milvusclient.NewListPartitionOption(collectionName)
+	var partitions []string
+	err := c.invoke(ctx, &OperationInfo{Name: "ListPartitions", Class: OpRead, Collection: collectionName}, func(ctx context.Context) (err error) {
+		partitions, err = c.cli.ListPartitions(ctx, option)
+		return err
+	})
+	return partitions, err
 }
 
 // CreateIndex 创建索引
@@ -352,19 +530,19 @@ func (c *client) ReleasePartitions(ctx context.Context, collectionName string, p
 // fieldName: 字段名称，例如"vector"
 // idx: 索引配置对象，例如index.NewIvfFlatIndex(entity.L2, 1024)
 func (c *client) CreateIndex(ctx context.Context, collectionName string, fieldName string, idx index.Index) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewCreateIndexOption(collectionName, fieldName, idx)
-	task, err := c.cli.CreateIndex(ctx, option)
-	if err != nil {
-		return err
-	}
-	return task.Await(ctx)
+	return c.invoke(ctx, &OperationInfo{Name: "CreateIndex", Class: OpDDL, Collection: collectionName, Args: map[string]any{"field": fieldName}}, func(ctx context.Context) error {
+		task, err := c.cli.CreateIndex(ctx, option)
+		if err != nil {
+			return err
+		}
+		return task.Await(ctx)
+	})
 }
 
 // DropIndex 删除索引
@@ -372,15 +550,47 @@ func (c *client) CreateIndex(ctx context.Context, collectionName string, fieldNa
 // collectionName: 集合名称
 // fieldName: 字段名称
 func (c *client) DropIndex(ctx context.Context, collectionName string, fieldName string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewDropIndexOption(collectionName, fieldName)
-	return c.cli.DropIndex(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "DropIndex", Class: OpDDL, Collection: collectionName, Args: map[string]any{"field": fieldName}}, func(ctx context.Context) error {
+		return c.cli.DropIndex(ctx, option)
+	})
+}
+
+// DescribeIndex 获取字段上的索引定义
+// ctx: 上下文，用于控制请求生命周期
+// collectionName: 集合名称，例如"my_collection"
+// fieldName: 字段名称，例如"vector"
+// 返回值: (索引定义，Params()包含index_type、metric_type及索引参数；字段上没有索引时为nil, 错误信息)
+func (c *client) DescribeIndex(ctx context.Context, collectionName string, fieldName string) (index.Index, error) {
+	if !c.begin() {
+		return nil, ErrClosed
+	}
+	defer c.end()
+
+	var idx index.Index
+	err := c.invoke(ctx, &OperationInfo{Name: "DescribeIndex", Class: OpRead, Collection: collectionName, Args: map[string]any{"field": fieldName}}, func(ctx context.Context) error {
+		indexNames, err := c.cli.ListIndexes(ctx, milvusclient.NewListIndexOption(collectionName).WithFieldName(fieldName))
+		if err != nil {
+			return err
+		}
+		if len(indexNames) == 0 {
+			return nil
+		}
+
+		option := milvusclient.NewDescribeIndexOption(collectionName, indexNames[0])
+		desc, err := c.cli.DescribeIndex(ctx, option)
+		if err != nil {
+			return err
+		}
+		idx = desc.Index
+		return nil
+	})
+	return idx, err
 }
 
 // Insert 插入数据
@@ -388,24 +598,55 @@ func (c *client) DropIndex(ctx context.Context, collectionName string, fieldName
 // collectionName: 集合名称，例如"my_collection"
 // partitionName: 分区名称，空字符串表示默认分区，例如"partition_1"或""
 // columns: 列数据，支持多个列，例如column.NewColumnFloatVector("vector", 128, vectors), column.NewColumnVarChar("text", texts)
-// 返回值: (插入数据的ID列, 错误信息)
+// 返回值: (插入数据的ID列, 错误信息)；数据超过MaxRequestSize时自动拆分为多次插入，部分失败时返回已插入数据的ID列
 func (c *client) Insert(ctx context.Context, collectionName string, partitionName string, columns ...column.Column) (column.Column, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return nil, errors.New("client is closed")
+	if !c.begin() {
+		return nil, ErrClosed
 	}
+	defer c.end()
 
-	option := milvusclient.NewColumnBasedInsertOption(collectionName, columns...)
-	if partitionName != "" {
-		option = option.WithPartition(partitionName)
+	// 自动生成主键的插入不是幂等操作，重试可能写入重复数据
+	var mayRetry func(ctx context.Context) bool
+	if !c.options.AllowAutoIDInsertRetry {
+		mayRetry = func(ctx context.Context) bool {
+			return !c.isAutoID(ctx, collectionName)
+		}
 	}
-	result, err := c.cli.Insert(ctx, option)
-	if err != nil {
-		return nil, err
+
+	// 超过请求大小上限时拆分为多次插入，按顺序合并返回的主键
+	var ids column.Column
+	chunks := splitColumns(columns, c.options.MaxRequestSize)
+	for i, chunk := range chunks {
+		option := milvusclient.NewColumnBasedInsertOption(collectionName, chunk...)
+		if partitionName != "" {
+			option = option.WithPartition(partitionName)
+		}
+		op := &OperationInfo{
+			Name:       "Insert",
+			Class:      OpWrite,
+			Collection: collectionName,
+			Args:       map[string]any{"partition": partitionName, "rows": columnRows(chunk)},
+			mayRetry:   mayRetry,
+		}
+		var result milvusclient.InsertResult
+		err := c.invoke(ctx, op, func(ctx context.Context) (err error) {
+			result, err = c.cli.Insert(ctx, option)
+			op.ResultRows = int(result.InsertCount)
+			return err
+		})
+		if err != nil {
+			if len(chunks) > 1 {
+				return ids, errors.Wrapf(err, "failed to insert chunk %d/%d", i+1, len(chunks))
+			}
+			return nil, err
+		}
+		if ids == nil {
+			ids = result.IDs
+		} else if err := appendColumn(ids, result.IDs); err != nil {
+			return ids, errors.Wrap(err, "failed to merge inserted ids")
+		}
 	}
-	return result.IDs, nil
+	return ids, nil
 }
 
 // Delete 删除数据
@@ -414,19 +655,26 @@ func (c *client) Insert(ctx context.Context, collectionName string, partitionNam
 // partitionName: 分区名称，空字符串表示默认分区，例如"partition_1"或""
 // expr: 删除条件表达式，例如"id > 0"、"id in [1,2,3]"、"text like 'test%'"
 func (c *client) Delete(ctx context.Context, collectionName string, partitionName string, expr string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewDeleteOption(collectionName).WithExpr(expr)
 	if partitionName != "" {
 		option = option.WithPartition(partitionName)
 	}
-	_, err := c.cli.Delete(ctx, option)
-	return err
+	op := &OperationInfo{
+		Name:       "Delete",
+		Class:      OpWrite,
+		Collection: collectionName,
+		Args:       map[string]any{"partition": partitionName, "expr": expr},
+	}
+	return c.invoke(ctx, op, func(ctx context.Context) error {
+		result, err := c.cli.Delete(ctx, option)
+		op.ResultRows = int(result.DeleteCount)
+		return err
+	})
 }
 
 // Search 搜索数据
@@ -440,21 +688,50 @@ func (c *client) Delete(ctx context.Context, collectionName string, partitionNam
 // topK: 返回最相似的前K个结果，例如5
 // expr: 过滤条件表达式，空字符串表示无过滤条件，例如"id > 0"
 // params: 搜索参数，例如map[string]string{"nprobe": "10"}
-// 返回值: (搜索结果列表, 错误信息)
+// 返回值: (搜索结果列表，与vectors一一对应, 错误信息)；查询向量超过MaxSearchNQ或MaxRequestSize时自动拆分
 func (c *client) Search(ctx context.Context, collectionName string, partitionNames []string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, expr string, params map[string]string) ([]milvusclient.ResultSet, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	if c.closed {
-		return nil, errors.New("client is closed")
+	if !c.begin() {
+		return nil, ErrClosed
 	}
-
-	option := milvusclient.NewSearchOption(collectionName, topK, vectors).
-		WithPartitions(partitionNames...).
-		WithOutputFields(outputFields...).
-		WithFilter(expr)
-
-	return c.cli.Search(ctx, option)
+	defer c.end()
+
+	// 查询向量过多或过大时拆分为多次搜索，结果按查询向量顺序合并
+	var results []milvusclient.ResultSet
+	for _, chunk := range splitVectors(vectors, c.options.MaxRequestSize, c.options.MaxSearchNQ) {
+		option := milvusclient.NewSearchOption(collectionName, topK, chunk).
+			WithPartitions(partitionNames...).
+			WithOutputFields(outputFields...).
+			WithFilter(expr)
+
+		op := &OperationInfo{
+			Name:       "Search",
+			Class:      OpRead,
+			Collection: collectionName,
+			Args: map[string]any{
+				"partitions":   partitionNames,
+				"outputFields": outputFields,
+				"nq":           len(chunk),
+				"topK":         topK,
+				"expr":         expr,
+				"vectorField":  vectorField,
+				"metricType":   string(metricType),
+			},
+		}
+		var resultSets []milvusclient.ResultSet
+		err := c.invoke(ctx, op, func(ctx context.Context) (err error) {
+			resultSets, err = c.cli.Search(ctx, option)
+			op.ResultRows = 0
+			for _, rs := range resultSets {
+				op.ResultRows += rs.ResultCount
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, resultSets...)
+	}
+	return results, nil
 }
 
 // Query 查询数据
@@ -465,19 +742,32 @@ func (c *client) Search(ctx context.Context, collectionName string, partitionNam
 // outputFields: 输出字段列表，例如[]string{"text", "id"}
 // 返回值: (查询结果列数据, 错误信息)
 func (c *client) Query(ctx context.Context, collectionName string, partitionNames []string, expr string, outputFields []string) ([]column.Column, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	if c.closed {
-		return nil, errors.New("client is closed")
+	if !c.begin() {
+		return nil, ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewQueryOption(collectionName).
 		WithPartitions(partitionNames...).
 		WithFilter(expr).
 		WithOutputFields(outputFields...)
 
-	resultSet, err := c.cli.Query(ctx, option)
+	op := &OperationInfo{
+		Name:       "Query",
+		Class:      OpRead,
+		Collection: collectionName,
+		Args: map[string]any{
+			"partitions":   partitionNames,
+			"expr":         expr,
+			"outputFields": outputFields,
+		},
+	}
+	var resultSet milvusclient.ResultSet
+	err := c.invoke(ctx, op, func(ctx context.Context) (err error) {
+		resultSet, err = c.cli.Query(ctx, option)
+		op.ResultRows = resultSet.ResultCount
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -490,49 +780,123 @@ func (c *client) Query(ctx context.Context, collectionName string, partitionName
 	return columns, nil
 }
 
+// QueryWithLimit 查询数据并限制返回行数
+// ctx: 上下文，用于控制请求生命周期
+// collectionName: 集合名称，例如"my_collection"
+// partitionNames: 分区名称列表，nil表示查询所有分区，例如[]string{"partition_1"}
+// expr: 查询条件表达式，空字符串表示不过滤，例如"id > 100"
+// outputFields: 输出字段列表，例如[]string{"text", "id"}
+// limit: 最大返回行数，例如1000；配合主键条件可实现按主键游标分页
+// 返回值: (查询结果列数据, 错误信息)
+func (c *client) QueryWithLimit(ctx context.Context, collectionName string, partitionNames []string, expr string, outputFields []string, limit int) ([]column.Column, error) {
+	if !c.begin() {
+		return nil, ErrClosed
+	}
+	defer c.end()
+
+	option := milvusclient.NewQueryOption(collectionName).
+		WithPartitions(partitionNames...).
+		WithFilter(expr).
+		WithOutputFields(outputFields...).
+		WithLimit(limit)
+
+	op := &OperationInfo{
+		Name:       "QueryWithLimit",
+		Class:      OpRead,
+		Collection: collectionName,
+		Args: map[string]any{
+			"partitions":   partitionNames,
+			"expr":         expr,
+			"outputFields": outputFields,
+			"limit":        limit,
+		},
+	}
+	var resultSet milvusclient.ResultSet
+	err := c.invoke(ctx, op, func(ctx context.Context) (err error) {
+		resultSet, err = c.cli.Query(ctx, option)
+		op.ResultRows = resultSet.ResultCount
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []column.Column
+	for _, field := range resultSet.Fields {
+		columns = append(columns, field)
+	}
+	return columns, nil
+}
+
 // CreateDatabase 创建数据库
 // ctx: 上下文，用于控制请求生命周期
 // dbName: 数据库名称，例如"my_database"
 func (c *client) CreateDatabase(ctx context.Context, dbName string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewCreateDatabaseOption(dbName)
-	return c.cli.CreateDatabase(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "CreateDatabase", Class: OpDDL, Args: map[string]any{"database": dbName}}, func(ctx context.Context) error {
+		return c.cli.CreateDatabase(ctx, option)
+	})
 }
 
 // DropDatabase 删除数据库
 // ctx: 上下文，用于控制请求生命周期
 // dbName: 要删除的数据库名称，例如"my_database"
 func (c *client) DropDatabase(ctx context.Context, dbName string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewDropDatabaseOption(dbName)
-	return c.cli.DropDatabase(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "DropDatabase", Class: OpDDL, Args: map[string]any{"database": dbName}}, func(ctx context.Context) error {
+		return c.cli.DropDatabase(ctx, option)
+	})
 }
 
 // UseDatabase 切换当前数据库
 // ctx: 上下文，用于控制请求生命周期
 // dbName: 要切换到的数据库名称，例如"my_database"
 func (c *client) UseDatabase(ctx context.Context, dbName string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewUseDatabaseOption(dbName)
-	return c.cli.UseDatabase(ctx, option)
+	err := c.invoke(ctx, &OperationInfo{Name: "UseDatabase", Class: OpRead, Args: map[string]any{"database": dbName}}, func(ctx context.Context) error {
+		return c.cli.UseDatabase(ctx, option)
+	})
+	if err == nil {
+		c.mu.Lock()
+		c.database = dbName
+		c.mu.Unlock()
+	}
+	// 切换数据库后集合名称指向不同的集合
+	c.autoID.Clear()
+	return err
+}
+
+// ListDatabases 列出所有数据库
+// ctx: 上下文，用于控制请求生命周期
+// 返回值: (数据库名称列表, 错误信息)
+func (c *client) ListDatabases(ctx context.Context) ([]string, error) {
+	if !c.begin() {
+		return nil, ErrClosed
+	}
+	defer c.end()
+
+	option := milvusclient.NewListDatabaseOption()
+	var databases []string
+	err := c.invoke(ctx, &OperationInfo{Name: "ListDatabases", Class: OpRead}, func(ctx context.Context) (err error) {
+		databases, err = c.cli.ListDatabase(ctx, option)
+		return err
+	})
+	return databases, err
 }
 
 // DescribeCollection 描述集合
@@ -540,15 +904,36 @@ func (c *client) UseDatabase(ctx context.Context, dbName string) error {
 // collectionName: 集合名称，例如"my_collection"
 // 返回值: (集合详细信息, 错误信息)
 func (c *client) DescribeCollection(ctx context.Context, collectionName string) (*entity.Collection, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	if c.closed {
-		return nil, errors.New("client is closed")
+	if !c.begin() {
+		return nil, ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewDescribeCollectionOption(collectionName)
-	return c.cli.DescribeCollection(ctx, option)
+	var coll *entity.Collection
+	err := c.invoke(ctx, &OperationInfo{Name: "DescribeCollection", Class: OpRead, Collection: collectionName}, func(ctx context.Context) (err error) {
+		coll, err = c.cli.DescribeCollection(ctx, option)
+		return err
+	})
+	return coll, err
+}
+
+// ListCollections 列出当前数据库中的所有集合
+// ctx: 上下文，用于控制请求生命周期
+// 返回值: (集合名称列表, 错误信息)
+func (c *client) ListCollections(ctx context.Context) ([]string, error) {
+	if !c.begin() {
+		return nil, ErrClosed
+	}
+	defer c.end()
+
+	option := milvusclient.NewListCollectionOption()
+	var collections []string
+	err := c.invoke(ctx, &OperationInfo{Name: "ListCollections", Class: OpRead}, func(ctx context.Context) (err error) {
+		collections, err = c.cli.ListCollections(ctx, option)
+		return err
+	})
+	return collections, err
 }
 
 // CreateAlias 创建集合别名
@@ -556,30 +941,30 @@ func (c *client) DescribeCollection(ctx context.Context, collectionName string)
 // collectionName: 集合名称，例如"my_collection"
 // alias: 别名，例如"my_alias"
 func (c *client) CreateAlias(ctx context.Context, collectionName string, alias string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewCreateAliasOption(collectionName, alias)
-	return c.cli.CreateAlias(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "CreateAlias", Class: OpDDL, Collection: collectionName, Args: map[string]any{"alias": alias}}, func(ctx context.Context) error {
+		return c.cli.CreateAlias(ctx, option)
+	})
 }
 
 // DropAlias 删除集合别名
 // ctx: 上下文，用于控制请求生命周期
 // alias: 要删除的别名，例如"my_alias"
 func (c *client) DropAlias(ctx context.Context, alias string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewDropAliasOption(alias)
-	return c.cli.DropAlias(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "DropAlias", Class: OpDDL, Args: map[string]any{"alias": alias}}, func(ctx context.Context) error {
+		return c.cli.DropAlias(ctx, option)
+	})
 }
 
 // AlterAlias 修改集合别名
@@ -587,15 +972,34 @@ func (c *client) DropAlias(ctx context.Context, alias string) error {
 // collectionName: 集合名称，例如"my_collection"
 // alias: 新的别名，例如"new_alias"
 func (c *client) AlterAlias(ctx context.Context, collectionName string, alias string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return errors.New("client is closed")
+	if !c.begin() {
+		return ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewAlterAliasOption(collectionName, alias)
-	return c.cli.AlterAlias(ctx, option)
+	return c.invoke(ctx, &OperationInfo{Name: "AlterAlias", Class: OpDDL, Collection: collectionName, Args: map[string]any{"alias": alias}}, func(ctx context.Context) error {
+		return c.cli.AlterAlias(ctx, option)
+	})
+}
+
+// ListAliases 列出集合的所有别名
+// ctx: 上下文，用于控制请求生命周期
+// collectionName: 集合名称，例如"my_collection"
+// 返回值: (别名列表, 错误信息)
+func (c *client) ListAliases(ctx context.Context, collectionName string) ([]string, error) {
+	if !c.begin() {
+		return nil, ErrClosed
+	}
+	defer c.end()
+
+	option := milvusclient.NewListAliasesOption(collectionName)
+	var aliases []string
+	err := c.invoke(ctx, &OperationInfo{Name: "ListAliases", Class: OpRead, Collection: collectionName}, func(ctx context.Context) (err error) {
+		aliases, err = c.cli.ListAliases(ctx, option)
+		return err
+	})
+	return aliases, err
 }
 
 // Compact 压缩集合
@@ -603,27 +1007,81 @@ func (c *client) AlterAlias(ctx context.Context, collectionName string, alias st
 // collectionName: 集合名称，例如"my_collection"
 // 返回值: (压缩任务ID, 错误信息)
 func (c *client) Compact(ctx context.Context, collectionName string) (int64, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.closed {
-		return 0, errors.New("client is closed")
+	if !c.begin() {
+		return 0, ErrClosed
 	}
+	defer c.end()
 
 	option := milvusclient.NewCompactOption(collectionName)
-	return c.cli.Compact(ctx, option)
+	var compactionID int64
+	err := c.invoke(ctx, &OperationInfo{Name: "Compact", Class: OpDDL, Collection: collectionName}, func(ctx context.Context) (err error) {
+		compactionID, err = c.cli.Compact(ctx, option)
+		return err
+	})
+	return compactionID, err
+}
+
+// CheckHealth 检查服务端健康状态
+// 不支持健康检查接口的旧版本服务端，能正常响应即视为健康
+// ctx: 上下文，用于控制请求生命周期
+// 返回值: 服务端健康时返回nil，服务端报告不健康时返回的错误匹配ErrUnavailable
+func (c *client) CheckHealth(ctx context.Context) error {
+	if !c.begin() {
+		return ErrClosed
+	}
+	defer c.end()
+
+	return c.invoke(ctx, &OperationInfo{Name: "CheckHealth", Class: OpRead}, c.checkHealth)
+}
+
+// probe 检查服务端健康状态，不经过拦截器、重试与熔断，用于故障转移的健康检查
+func (c *client) probe(ctx context.Context) error {
+	if !c.begin() {
+		return ErrClosed
+	}
+	defer c.end()
+
+	return convertError("CheckHealth", c.checkHealth(ctx))
+}
+
+// checkHealth 调用服务端的健康检查接口
+func (c *client) checkHealth(ctx context.Context) error {
+	resp, err := c.cli.GetService().CheckHealth(ctx, &milvuspb.CheckHealthRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := merr.Error(resp.GetStatus()); err != nil {
+		return err
+	}
+	if !resp.GetIsHealthy() {
+		return &Error{Op: "CheckHealth", Kind: ErrUnavailable, Err: errors.Errorf("milvus is unhealthy: %s", strings.Join(resp.GetReasons(), "; "))}
+	}
+	return nil
 }
 
 // Close 关闭客户端
+// 新的请求立即返回ErrClosed，等待进行中的请求结束后关闭连接
 // 返回值: 错误信息
 func (c *client) Close() error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	if c.closed {
+		c.mu.Unlock()
 		return nil
 	}
-
 	c.closed = true
-	return c.cli.Close(context.Background())
+	c.mu.Unlock()
+
+	c.inflight.Wait()
+	if c.connLog != nil {
+		c.connLog.closing.Store(true)
+	}
+	if err := c.cli.Close(context.Background()); err != nil {
+		c.logger.Warn("failed to close milvus client", slog.String("address", c.options.Address), slog.Any("error", err))
+		return err
+	}
+	c.logger.Info("milvus client closed", slog.String("address", c.options.Address))
+	return nil
 }
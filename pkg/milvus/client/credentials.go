@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationHeader Milvus读取认证信息的gRPC元数据键
+const authorizationHeader = "authorization"
+
+// notAuthenticatedCode Milvus未认证的错误码
+const notAuthenticatedCode = 1400
+
+// Credentials 一次请求使用的认证信息，APIKey与用户名/密码互斥，优先使用APIKey
+type Credentials struct {
+	Username string // 用户名
+	Password string // 密码
+	APIKey   string // API密钥或令牌
+}
+
+// header 返回authorization元数据的值，没有凭据时返回空字符串
+func (c Credentials) header() string {
+	switch {
+	case c.APIKey != "":
+		return base64.StdEncoding.EncodeToString([]byte(c.APIKey))
+	case c.Username != "" || c.Password != "":
+		return base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))
+	default:
+		return ""
+	}
+}
+
+// CredentialProvider 凭据提供者，用于短期令牌等需要轮换的凭据
+//
+// 客户端在每次gRPC调用前调用Credentials，实现应当缓存凭据，避免每次请求访问外部系统。
+// 服务端因认证失败拒绝请求时，客户端调用Invalidate，再次获取凭据，凭据变化时重试一次。
+// 实现需要支持并发调用。
+type CredentialProvider interface {
+	// Credentials 返回当前凭据
+	Credentials(ctx context.Context) (Credentials, error)
+	// Invalidate 通知凭据被服务端拒绝，之后的Credentials应当重新获取凭据
+	Invalidate(creds Credentials)
+}
+
+// CredentialFunc 从外部系统获取凭据，返回凭据与过期时间，过期时间为零值表示不过期
+type CredentialFunc func(ctx context.Context) (Credentials, time.Time, error)
+
+// refreshingCredentials 缓存凭据并在过期前刷新的凭据提供者
+type refreshingCredentials struct {
+	fetch         CredentialFunc
+	refreshBefore time.Duration
+
+	mu      sync.Mutex
+	creds   Credentials
+	expires time.Time
+	valid   bool // 是否已获取凭据且未失效
+}
+
+// NewRefreshingCredentials 创建缓存凭据的提供者，凭据在过期前refreshBefore刷新
+//
+// 刷新失败时，若当前凭据尚未过期则继续使用，否则返回错误；被服务端拒绝后下次请求立即刷新。
+// 刷新期间持有锁，并发请求等待同一次刷新。
+// fetch: 获取凭据的函数，例如调用密钥管理服务
+// refreshBefore: 提前刷新的时间，避免凭据在请求过程中过期
+func NewRefreshingCredentials(fetch CredentialFunc, refreshBefore time.Duration) CredentialProvider {
+	return &refreshingCredentials{fetch: fetch, refreshBefore: refreshBefore}
+}
+
+// Credentials 返回缓存的凭据，即将过期或已失效时刷新
+func (r *refreshingCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if r.valid && (r.expires.IsZero() || now.Add(r.refreshBefore).Before(r.expires)) {
+		return r.creds, nil
+	}
+
+	creds, expires, err := r.fetch(ctx)
+	if err != nil {
+		if r.valid && (r.expires.IsZero() || now.Before(r.expires)) {
+			return r.creds, nil
+		}
+		return Credentials{}, errors.Wrap(err, "failed to fetch milvus credentials")
+	}
+	r.creds, r.expires, r.valid = creds, expires, true
+	return creds, nil
+}
+
+// Invalidate 使被拒绝的凭据失效，凭据已经刷新时忽略
+func (r *refreshingCredentials) Invalidate(creds Credentials) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.creds == creds {
+		r.valid = false
+	}
+}
+
+// NewFileCredentials 创建从文件读取令牌的提供者，用于挂载到容器中、由外部定期轮换的令牌
+//
+// 文件内容去除首尾空白后作为APIKey，Milvus的令牌也可以是"用户名:密码"的形式。
+// 每隔interval重新读取文件，认证失败时立即重新读取。
+// path: 令牌文件路径
+// interval: 重新读取文件的间隔，0表示只在认证失败时重新读取
+func NewFileCredentials(path string, interval time.Duration) CredentialProvider {
+	return NewRefreshingCredentials(func(ctx context.Context) (Credentials, time.Time, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Credentials{}, time.Time{}, errors.Wrap(err, "failed to read token file")
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return Credentials{}, time.Time{}, errors.Errorf("token file %s is empty", path)
+		}
+		var expires time.Time
+		if interval > 0 {
+			expires = time.Now().Add(interval)
+		}
+		return Credentials{APIKey: token}, expires, nil
+	}, 0)
+}
+
+// withCredentials 在gRPC元数据中设置凭据
+func withCredentials(ctx context.Context, creds Credentials) context.Context {
+	if value := creds.header(); value != "" {
+		return metadata.AppendToOutgoingContext(ctx, authorizationHeader, value)
+	}
+	return ctx
+}
+
+// isAuthFailure 判断是否为认证失败，服务端通过gRPC状态码或响应中的错误码返回
+func isAuthFailure(err error, reply any) bool {
+	if status.Code(err) == codes.Unauthenticated {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	var s *commonpb.Status
+	switch r := reply.(type) {
+	case *commonpb.Status:
+		s = r
+	case interface{ GetStatus() *commonpb.Status }:
+		s = r.GetStatus()
+	}
+	return s.GetCode() == notAuthenticatedCode
+}
+
+// credentialUnaryInterceptor 为每次一元调用设置凭据，认证失败且凭据刷新后重试一次
+func credentialUnaryInterceptor(provider CredentialProvider) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		creds, err := provider.Credentials(ctx)
+		if err != nil {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+		err = invoker(withCredentials(ctx, creds), method, req, reply, cc, opts...)
+		if !isAuthFailure(err, reply) {
+			return err
+		}
+
+		provider.Invalidate(creds)
+		refreshed, refreshErr := provider.Credentials(ctx)
+		if refreshErr != nil || refreshed == creds {
+			return err
+		}
+		return invoker(withCredentials(ctx, refreshed), method, req, reply, cc, opts...)
+	}
+}
+
+// credentialStreamInterceptor 为每次流式调用设置凭据
+func credentialStreamInterceptor(provider CredentialProvider) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		creds, err := provider.Credentials(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return streamer(withCredentials(ctx, creds), desc, cc, method, opts...)
+	}
+}
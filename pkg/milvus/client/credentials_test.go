@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// authServer 校验authorization元数据的gRPC服务，凭据正确时返回Unimplemented，否则返回Unauthenticated
+type authServer struct {
+	mu      sync.Mutex
+	token   string     // 接受的令牌，即APIKey或"用户名:密码"
+	headers [][]string // 每次请求收到的authorization元数据
+}
+
+// setToken 设置接受的令牌
+func (s *authServer) setToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// received 返回收到的authorization元数据
+func (s *authServer) received() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.headers...)
+}
+
+// handle 处理所有方法的请求
+func (s *authServer) handle(_ any, stream grpc.ServerStream) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	values := md.Get(authorizationHeader)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers = append(s.headers, values)
+	expected := base64.StdEncoding.EncodeToString([]byte(s.token))
+	if len(values) != 1 || values[0] != expected {
+		return status.Error(codes.Unauthenticated, "auth check failure")
+	}
+	return status.Error(codes.Unimplemented, "not implemented")
+}
+
+// startAuthServer 启动校验凭据的gRPC服务
+func startAuthServer(t *testing.T, token string) (*authServer, string) {
+	srv := &authServer{token: token}
+	return srv, testserver.Start(t, testserver.WithHandler(srv.handle))
+}
+
+// TestCredentialProvider 测试凭据提供者
+func TestCredentialProvider(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("每次请求获取凭据", func(t *testing.T) {
+		srv, addr := startAuthServer(t, "token-1")
+		var fetches atomic.Int32
+		provider := NewRefreshingCredentials(func(ctx context.Context) (Credentials, time.Time, error) {
+			fetches.Add(1)
+			return Credentials{APIKey: "token-1"}, time.Time{}, nil
+		}, 0)
+
+		cli, err := NewWithOptions(ctx, WithAddress(addr), WithAuth("root", "static"), WithCredentialProvider(provider))
+		require.NoError(t, err, "连接握手使用提供者的凭据")
+		defer cli.Close()
+		require.NoError(t, cli.CheckHealth(ctx))
+		require.NoError(t, cli.CheckHealth(ctx))
+
+		assert.Equal(t, int32(1), fetches.Load(), "凭据不过期时使用缓存")
+		for _, values := range srv.received() {
+			assert.Len(t, values, 1, "不发送静态凭据")
+		}
+	})
+
+	t.Run("认证失败时刷新凭据并重试", func(t *testing.T) {
+		srv, addr := startAuthServer(t, "token-1")
+		var version atomic.Int32
+		version.Store(1)
+		provider := NewRefreshingCredentials(func(ctx context.Context) (Credentials, time.Time, error) {
+			if version.Load() == 1 {
+				return Credentials{Username: "root", Password: "old"}, time.Time{}, nil
+			}
+			return Credentials{Username: "root", Password: "new"}, time.Time{}, nil
+		}, 0)
+		srv.setToken("root:old")
+
+		cli, err := NewWithOptions(ctx, WithAddress(addr), WithCredentialProvider(provider), WithRetryPolicy(OpRead, nil))
+		require.NoError(t, err)
+		defer cli.Close()
+		require.NoError(t, cli.CheckHealth(ctx))
+
+		// 密码轮换后第一次请求被拒绝，刷新凭据后重试成功
+		srv.setToken("root:new")
+		version.Store(2)
+		require.NoError(t, cli.CheckHealth(ctx))
+		headers := srv.received()
+		require.GreaterOrEqual(t, len(headers), 2)
+		assert.Equal(t, []string{base64.StdEncoding.EncodeToString([]byte("root:old"))}, headers[len(headers)-2])
+		assert.Equal(t, []string{base64.StdEncoding.EncodeToString([]byte("root:new"))}, headers[len(headers)-1])
+	})
+
+	t.Run("凭据未变化时不重试", func(t *testing.T) {
+		srv, addr := startAuthServer(t, "token-1")
+		cli, err := NewWithOptions(ctx, WithAddress(addr), WithRetryPolicy(OpRead, nil),
+			WithCredentialProvider(NewRefreshingCredentials(func(ctx context.Context) (Credentials, time.Time, error) {
+				return Credentials{APIKey: "token-1"}, time.Time{}, nil
+			}, 0)))
+		require.NoError(t, err)
+		defer cli.Close()
+
+		srv.setToken("token-2")
+		before := len(srv.received())
+		err = cli.CheckHealth(ctx)
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+		assert.Len(t, srv.received(), before+1, "刷新后凭据相同，不再重试")
+	})
+
+	t.Run("获取凭据失败", func(t *testing.T) {
+		_, addr := startAuthServer(t, "token-1")
+		_, err := NewWithOptions(ctx, WithAddress(addr), WithCredentialProvider(NewRefreshingCredentials(
+			func(ctx context.Context) (Credentials, time.Time, error) {
+				return Credentials{}, time.Time{}, errors.New("secret manager unavailable")
+			}, 0)))
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+		assert.ErrorContains(t, err, "secret manager unavailable")
+	})
+
+	t.Run("从文件读取令牌", func(t *testing.T) {
+		srv, addr := startAuthServer(t, "token-1")
+		path := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(path, []byte("token-1\n"), 0o600))
+
+		cli, err := NewWithOptions(ctx, WithAddress(addr), WithRetryPolicy(OpRead, nil),
+			WithCredentialProvider(NewFileCredentials(path, time.Hour)))
+		require.NoError(t, err)
+		defer cli.Close()
+
+		// 令牌轮换后，认证失败时立即重新读取文件
+		require.NoError(t, os.WriteFile(path, []byte("token-2\n"), 0o600))
+		srv.setToken("token-2")
+		assert.NoError(t, cli.CheckHealth(ctx))
+	})
+}
+
+// TestRefreshingCredentials 测试凭据的缓存与刷新
+func TestRefreshingCredentials(t *testing.T) {
+	ctx := context.Background()
+	var calls atomic.Int32
+	var fail atomic.Bool
+	provider := NewRefreshingCredentials(func(ctx context.Context) (Credentials, time.Time, error) {
+		n := calls.Add(1)
+		if fail.Load() {
+			return Credentials{}, time.Time{}, errors.New("unavailable")
+		}
+		return Credentials{APIKey: string(rune('a' + n - 1))}, time.Now().Add(400 * time.Millisecond), nil
+	}, 200*time.Millisecond)
+
+	creds, err := provider.Credentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a", creds.APIKey)
+	creds, err = provider.Credentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a", creds.APIKey, "未到刷新时间时使用缓存")
+
+	t.Run("过期前刷新", func(t *testing.T) {
+		time.Sleep(250 * time.Millisecond)
+		creds, err := provider.Credentials(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "b", creds.APIKey)
+	})
+
+	t.Run("刷新失败时继续使用未过期的凭据", func(t *testing.T) {
+		fail.Store(true)
+		time.Sleep(250 * time.Millisecond)
+		creds, err := provider.Credentials(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "b", creds.APIKey)
+
+		time.Sleep(200 * time.Millisecond)
+		_, err = provider.Credentials(ctx)
+		assert.ErrorContains(t, err, "failed to fetch milvus credentials")
+	})
+
+	t.Run("只使被拒绝的凭据失效", func(t *testing.T) {
+		fail.Store(false)
+		creds, err := provider.Credentials(ctx)
+		require.NoError(t, err)
+		provider.Invalidate(Credentials{APIKey: "stale"})
+		again, err := provider.Credentials(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, creds, again)
+
+		provider.Invalidate(creds)
+		refreshed, err := provider.Credentials(ctx)
+		require.NoError(t, err)
+		assert.NotEqual(t, creds, refreshed)
+	})
+}
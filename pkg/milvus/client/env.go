@@ -0,0 +1,130 @@
+package client
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// envField 环境变量与Options字段的映射，URI参数之外的连接信息
+type envField struct {
+	name string
+	set  func(o *Options, v string) error
+}
+
+// envFields 连接信息对应的环境变量，用户名、密码与APIKey在FromEnv中单独处理
+var envFields = []envField{
+	{
+		name: "address",
+		set: func(o *Options, v string) error {
+			o.Address = v
+			return nil
+		},
+	},
+	{
+		name: "db_name",
+		set: func(o *Options, v string) error {
+			o.DBName = v
+			return nil
+		},
+	},
+	{
+		name: "tls",
+		set: func(o *Options, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return errors.Errorf("invalid boolean %q", v)
+			}
+			o.EnableTLSAuth = b
+			return nil
+		},
+	},
+}
+
+// envName 返回配置项对应的环境变量名，例如前缀MILVUS与max_retry对应MILVUS_MAX_RETRY
+func envName(prefix, name string) string {
+	name = strings.ToUpper(name)
+	if prefix = strings.TrimSuffix(prefix, "_"); prefix != "" {
+		return prefix + "_" + name
+	}
+	return name
+}
+
+// lookupEnv 读取环境变量，未设置或为空时返回false
+func lookupEnv(name string) (string, bool) {
+	v, ok := os.LookupEnv(name)
+	if !ok || strings.TrimSpace(v) == "" {
+		return "", false
+	}
+	return v, true
+}
+
+// FromEnv 从环境变量读取配置，未设置或为空的变量对应的配置保持不变，变量取值无效时NewWithOptions返回错误
+//
+// 变量名为前缀加下划线与大写的配置名，以前缀MILVUS为例：
+//   - MILVUS_URI：连接URI，格式见ParseURI，最先应用，其余变量覆盖URI中的配置
+//   - MILVUS_ADDRESS、MILVUS_DB_NAME、MILVUS_TLS：服务地址、数据库、是否启用TLS
+//   - MILVUS_USERNAME、MILVUS_PASSWORD、MILVUS_API_KEY：认证信息，API_KEY与用户名/密码互斥，同时设置时报错
+//   - 其余变量与ParseURI支持的参数一一对应，例如MILVUS_TIMEOUT、MILVUS_READ_TIMEOUT、MILVUS_MAX_RETRY、
+//     MILVUS_MAX_RETRY_BACKOFF、MILVUS_KEEPALIVE、MILVUS_KEEPALIVE_TIMEOUT、MILVUS_PERMIT_WITHOUT_STREAM、
+//     MILVUS_BASE_DELAY、MILVUS_MULTIPLIER、MILVUS_JITTER、MILVUS_MAX_DELAY、MILVUS_MIN_CONNECT_TIMEOUT、
+//     MILVUS_MAX_RECV_MSG_SIZE、MILVUS_MAX_REQUEST_SIZE、MILVUS_MAX_SEARCH_NQ、MILVUS_SLOW_THRESHOLD、MILVUS_DISABLE_CONN
+//   - MILVUS_TLS_CA_FILE、MILVUS_TLS_CERT_FILE、MILVUS_TLS_KEY_FILE、MILVUS_TLS_SERVER_NAME、MILVUS_TLS_MIN_VERSION、
+//     MILVUS_TLS_RELOAD_INTERVAL：TLS与mTLS配置，设置时启用TLS
+//
+// 时间间隔使用"5s"、"1m30s"这样的格式，布尔值使用true/false
+// prefix: 变量名前缀，例如"MILVUS"，为空时不加前缀
+func FromEnv(prefix string) Option {
+	return func(o *Options) {
+		if err := applyEnv(o, prefix); err != nil && o.err == nil {
+			o.err = err
+		}
+	}
+}
+
+// applyEnv 将环境变量中的配置写入o
+func applyEnv(o *Options, prefix string) error {
+	if v, ok := lookupEnv(envName(prefix, "uri")); ok {
+		if err := applyURI(o, v); err != nil {
+			return errors.Wrapf(err, "invalid environment variable %s", envName(prefix, "uri"))
+		}
+	}
+
+	for _, f := range envFields {
+		name := envName(prefix, f.name)
+		if v, ok := lookupEnv(name); ok {
+			if err := f.set(o, v); err != nil {
+				return errors.Wrapf(err, "invalid environment variable %s", name)
+			}
+		}
+	}
+
+	username, hasUsername := lookupEnv(envName(prefix, "username"))
+	password, hasPassword := lookupEnv(envName(prefix, "password"))
+	if _, hasAPIKey := lookupEnv(envName(prefix, "api_key")); hasAPIKey && (hasUsername || hasPassword) {
+		return errors.Errorf("environment variables %s and %s/%s are mutually exclusive",
+			envName(prefix, "api_key"), envName(prefix, "username"), envName(prefix, "password"))
+	}
+	if hasUsername || hasPassword {
+		if !hasUsername {
+			username = o.Username
+		}
+		if !hasPassword {
+			password = o.Password
+		}
+		WithAuth(username, password)(o)
+	}
+
+	// timeout先于各类别的超时应用，使READ_TIMEOUT等覆盖TIMEOUT
+	for _, p := range uriParams {
+		name := envName(prefix, p.name)
+		if v, ok := lookupEnv(name); ok {
+			if err := p.set(o, strings.TrimSpace(v)); err != nil {
+				return errors.Wrapf(err, "invalid environment variable %s", name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 错误分类，可通过errors.Is判断，例如errors.Is(err, client.ErrCollectionNotFound)
+var (
+	ErrClosed             = errors.New("client is closed")        // 客户端已关闭
+	ErrCollectionNotFound = errors.New("collection not found")    // 集合不存在
+	ErrPartitionNotFound  = errors.New("partition not found")     // 分区不存在
+	ErrDatabaseNotFound   = errors.New("database not found")      // 数据库不存在
+	ErrIndexNotFound      = errors.New("index not found")         // 索引不存在
+	ErrAliasNotFound      = errors.New("alias not found")         // 别名不存在
+	ErrAlreadyExists      = errors.New("already exists")          // 集合、别名、索引等已存在
+	ErrNotLoaded          = errors.New("not loaded")              // 集合或分区未加载
+	ErrSchemaMismatch     = errors.New("schema mismatch")         // 数据与集合模式不匹配
+	ErrInvalidParameter   = errors.New("invalid parameter")       // 请求参数错误
+	ErrRateLimited        = errors.New("rate limited")            // 触发服务端限流或等待客户端限流超时
+	ErrQuotaExceeded      = errors.New("quota exceeded")          // 超出内存、磁盘或数量配额
+	ErrTimeout            = errors.New("timeout")                 // 请求超时
+	ErrUnavailable        = errors.New("service unavailable")     // 服务不可用或未就绪
+	ErrPermissionDenied   = errors.New("permission denied")       // 未认证或无权限
+	ErrUnsupported        = errors.New("unsupported operation")   // 服务端不支持的操作
+	ErrCircuitOpen        = errors.New("circuit breaker is open") // 熔断器打开，请求未发送到服务端
+)
+
+// Error Milvus操作错误，保留原始错误与服务端错误码
+// errors.Is可同时匹配错误分类与原始错误，例如context.DeadlineExceeded
+type Error struct {
+	Op        string // 操作名称，例如"Insert"
+	Kind      error  // 错误分类，例如ErrCollectionNotFound，无法分类时为nil
+	Code      int32  // Milvus错误码，无法识别的错误为65535，gRPC与上下文错误为0
+	Retryable bool   // 服务端或错误分类表明该错误可重试
+	Err       error  // 原始错误
+}
+
+// Error 返回错误信息
+func (e *Error) Error() string {
+	if e.Op == "" {
+		return e.Err.Error()
+	}
+	return e.Op + ": " + e.Err.Error()
+}
+
+// Unwrap 返回错误分类与原始错误，供errors.Is/As使用
+func (e *Error) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// IsRetryable 判断错误是否可重试
+func IsRetryable(err error) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Retryable
+	}
+	return false
+}
+
+// convertError 将SDK返回的错误转换为*Error，nil与已转换的错误原样返回
+// op: 操作名称，例如"Insert"
+func convertError(op string, err error) error {
+	if err == nil || errors.Is(err, ErrClosed) {
+		return err
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	e = &Error{Op: op, Err: err}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		e.Kind = ErrTimeout
+	case errors.Is(err, context.Canceled):
+		// 调用方主动取消，不归类
+	default:
+		if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+			e.Kind = grpcKind(s)
+		} else {
+			e.Code = merr.Code(err)
+			e.Kind = codeKind(e.Code, err.Error())
+			e.Retryable = merr.IsRetryableErr(err)
+		}
+	}
+	switch e.Kind {
+	case ErrRateLimited, ErrUnavailable, ErrTimeout:
+		e.Retryable = true
+	}
+	return e
+}
+
+// grpcKind 根据gRPC状态码分类错误
+func grpcKind(s *status.Status) error {
+	switch s.Code() {
+	case codes.DeadlineExceeded:
+		return ErrTimeout
+	case codes.Unavailable:
+		return ErrUnavailable
+	case codes.ResourceExhausted:
+		// 超过gRPC消息大小上限同样返回ResourceExhausted，但重试无意义
+		if strings.Contains(s.Message(), "larger than max") {
+			return ErrInvalidParameter
+		}
+		return ErrRateLimited
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return ErrPermissionDenied
+	case codes.InvalidArgument, codes.OutOfRange:
+		return ErrInvalidParameter
+	case codes.AlreadyExists:
+		return ErrAlreadyExists
+	case codes.Unimplemented:
+		return ErrUnsupported
+	default:
+		return nil
+	}
+}
+
+// codeKind 根据Milvus错误码分类错误，错误码无法区分时根据错误信息判断
+// 错误码定义见 github.com/milvus-io/milvus/pkg/v2/util/merr
+func codeKind(code int32, msg string) error {
+	switch code {
+	case 100:
+		return ErrCollectionNotFound
+	case 200:
+		return ErrPartitionNotFound
+	case 800:
+		return ErrDatabaseNotFound
+	case 700:
+		return ErrIndexNotFound
+	case 1600:
+		return ErrAliasNotFound
+	case 301, 702, 1601, 1602:
+		return ErrAlreadyExists
+	case 101, 103, 201, 202:
+		return ErrNotLoaded
+	case 105, 109, 1700, 1804, 1806:
+		return ErrSchemaMismatch
+	case 802, 1100, 1101, 1102, 1701, 1801, 1802:
+		return ErrInvalidParameter
+	case 4, 8, 1807:
+		return ErrRateLimited
+	case 3, 7, 9, 102, 250, 801:
+		return ErrQuotaExceeded
+	case 1, 2, 12:
+		return ErrUnavailable
+	case 1400, 1401, 1800:
+		return ErrPermissionDenied
+	case 10, 3000:
+		return ErrUnsupported
+	case merr.TimeoutCode:
+		return ErrTimeout
+	}
+
+	// 部分错误（如重复创建数据库或集合）服务端只返回通用错误码
+	msg = strings.ToLower(msg)
+	switch {
+	case strings.Contains(msg, "already exist"):
+		return ErrAlreadyExists
+	case strings.Contains(msg, "collection not found"), strings.Contains(msg, "can't find collection"):
+		return ErrCollectionNotFound
+	case strings.Contains(msg, "database not found"):
+		return ErrDatabaseNotFound
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestConvertError 测试错误分类
+func TestConvertError(t *testing.T) {
+	t.Run("服务端状态码", func(t *testing.T) {
+		cases := []struct {
+			status *commonpb.Status
+			kind   error
+		}{
+			{&commonpb.Status{Code: 100, Reason: "collection not found[collection=c1]"}, ErrCollectionNotFound},
+			{&commonpb.Status{Code: 200, Reason: "partition not found"}, ErrPartitionNotFound},
+			{&commonpb.Status{Code: 1602, Reason: "alias already exist"}, ErrAlreadyExists},
+			{&commonpb.Status{Code: 109, Reason: "collection schema mismatch"}, ErrSchemaMismatch},
+			{&commonpb.Status{Code: 101, Reason: "collection not loaded"}, ErrNotLoaded},
+			{&commonpb.Status{Code: 65535, Reason: "database already exist: db1"}, ErrAlreadyExists},
+		}
+		for _, c := range cases {
+			err := convertError("Op", merr.Error(c.status))
+			assert.ErrorIs(t, err, c.kind, c.status.Reason)
+
+			var e *Error
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, c.status.Code, e.Code)
+			assert.Equal(t, "Op", e.Op)
+			assert.False(t, e.Retryable)
+		}
+	})
+
+	t.Run("限流可重试", func(t *testing.T) {
+		err := convertError("Insert", merr.Error(&commonpb.Status{Code: 8, Reason: "rate limit exceeded", Retriable: true}))
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.True(t, IsRetryable(err))
+		assert.Contains(t, err.Error(), "Insert: rate limit exceeded")
+	})
+
+	t.Run("gRPC状态", func(t *testing.T) {
+		err := convertError("Search", status.Error(codes.Unavailable, "connection refused"))
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.True(t, IsRetryable(err))
+
+		err = convertError("Insert", status.Error(codes.ResourceExhausted, "grpc: received message larger than max (100 vs. 10)"))
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+		assert.False(t, IsRetryable(err))
+	})
+
+	t.Run("上下文错误", func(t *testing.T) {
+		err := convertError("Query", context.DeadlineExceeded)
+		assert.ErrorIs(t, err, ErrTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		err = convertError("Query", context.Canceled)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, IsRetryable(err))
+	})
+
+	t.Run("保持原样", func(t *testing.T) {
+		assert.NoError(t, convertError("Op", nil))
+		assert.Equal(t, ErrClosed, convertError("Op", ErrClosed))
+
+		converted := convertError("Op", context.DeadlineExceeded)
+		wrapped := errors.Wrap(converted, "outer")
+		assert.Equal(t, wrapped, convertError("Other", wrapped))
+	})
+}
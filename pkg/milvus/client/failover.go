@@ -0,0 +1,628 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/pkg/errors"
+)
+
+// Endpoint 故障转移的一个服务地址
+type Endpoint struct {
+	Address  string // Milvus服务地址，格式：host:port
+	Priority int    // 优先级，数值越小越优先，相同优先级按配置顺序
+}
+
+// FailoverPolicy 多地址故障转移策略
+// 值为0的字段使用DefaultFailoverPolicy中的默认值，FailbackDelay除外
+type FailoverPolicy struct {
+	HealthCheckInterval time.Duration       // 健康检查间隔，每次检查所有地址
+	HealthCheckTimeout  time.Duration       // 单个地址建立连接与健康检查的超时时间
+	FailureThreshold    int                 // 当前地址连续失败（健康检查失败或请求返回服务不可用）达到该次数时切换
+	FailbackDelay       time.Duration       // 更高优先级的地址恢复后持续健康该时长才切回，避免来回切换，0表示恢复后立即切回
+	OnSwitch            func(FailoverEvent) // 每次切换后同步调用，不应阻塞
+}
+
+// DefaultFailoverPolicy 返回默认的故障转移策略
+func DefaultFailoverPolicy() *FailoverPolicy {
+	return &FailoverPolicy{
+		HealthCheckInterval: 10 * time.Second,
+		HealthCheckTimeout:  5 * time.Second,
+		FailureThreshold:    3,
+		FailbackDelay:       30 * time.Second,
+	}
+}
+
+// FailoverEvent 一次地址切换
+type FailoverEvent struct {
+	From     string    // 切换前的地址
+	To       string    // 切换后的地址
+	Failback bool      // 是否为恢复后切回更高优先级的地址
+	Err      error     // 触发故障转移的错误，切回时为nil
+	Time     time.Time // 切换时间
+}
+
+// endpointState 一个地址的连接与健康状态
+type endpointState struct {
+	Endpoint
+	cli          *client      // 该地址的客户端，尚未建立连接时为nil，由failoverClient.mu保护
+	database     string       // 客户端当前的数据库，由failoverClient.switchMu保护
+	failures     atomic.Int32 // 连续失败次数
+	healthySince time.Time    // 持续健康的起始时间，零值表示不健康，只在健康检查协程中访问
+}
+
+// failoverClient 在多个地址之间故障转移的客户端，实现Client接口
+//
+// 所有请求发送到当前地址，地址切换只在健康检查协程中进行：当前地址连续失败达到阈值时，
+// 按优先级切换到第一个健康的地址；更高优先级的地址持续健康FailbackDelay后切回。
+type failoverClient struct {
+	options   *Options
+	policy    *FailoverPolicy
+	logger    *slog.Logger
+	endpoints []*endpointState // 按优先级排序
+
+	mu       sync.RWMutex
+	active   int // 当前地址的下标
+	database string
+	closed   bool
+
+	switchMu sync.Mutex    // 串行化地址切换与UseDatabase，保证切换后的地址使用当前数据库
+	trigger  chan error    // 请求失败达到阈值时通知健康检查协程立即切换
+	stop     chan struct{} // 关闭时通知健康检查协程退出
+	done     chan struct{} // 健康检查协程已退出
+}
+
+// newFailoverClient 按优先级连接第一个可用的地址并启动健康检查
+func newFailoverClient(ctx context.Context, options *Options) (*failoverClient, error) {
+	defaults := DefaultFailoverPolicy()
+	policy := defaults
+	if options.Failover != nil {
+		policy = new(FailoverPolicy)
+		*policy = *options.Failover
+		if policy.HealthCheckInterval <= 0 {
+			policy.HealthCheckInterval = defaults.HealthCheckInterval
+		}
+		if policy.HealthCheckTimeout <= 0 {
+			policy.HealthCheckTimeout = defaults.HealthCheckTimeout
+		}
+		if policy.FailureThreshold <= 0 {
+			policy.FailureThreshold = defaults.FailureThreshold
+		}
+	}
+
+	f := &failoverClient{
+		options:  options,
+		policy:   policy,
+		logger:   newLogger(options),
+		database: options.DBName,
+		trigger:  make(chan error, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	seen := make(map[string]bool)
+	for _, ep := range options.Endpoints {
+		if strings.TrimSpace(ep.Address) == "" {
+			return nil, errors.New("failover endpoint address must not be empty")
+		}
+		if seen[ep.Address] {
+			return nil, errors.Errorf("duplicate failover endpoint %s", ep.Address)
+		}
+		seen[ep.Address] = true
+		f.endpoints = append(f.endpoints, &endpointState{Endpoint: ep})
+	}
+	sort.SliceStable(f.endpoints, func(i, j int) bool {
+		return f.endpoints[i].Priority < f.endpoints[j].Priority
+	})
+
+	var causes []string
+	var lastErr error
+	for i, ep := range f.endpoints {
+		if err := f.connect(ctx, ep); err != nil {
+			causes = append(causes, ep.Address+": "+err.Error())
+			lastErr = err
+			continue
+		}
+		f.active = i
+		go f.run()
+		return f, nil
+	}
+	return nil, errors.Wrapf(lastErr, "failed to connect to any milvus endpoint (%s)", strings.Join(causes, "; "))
+}
+
+// connect 为地址创建客户端，超时时间为HealthCheckTimeout
+func (f *failoverClient) connect(ctx context.Context, ep *endpointState) error {
+	options := *f.options
+	options.Address = ep.Address
+	options.Endpoints = nil
+	options.Failover = nil
+
+	f.switchMu.Lock()
+	options.DBName = f.database
+	f.switchMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, f.policy.HealthCheckTimeout)
+	defer cancel()
+	cli, err := newClient(ctx, &options)
+	if err != nil {
+		return err
+	}
+
+	f.switchMu.Lock()
+	ep.database = options.DBName
+	f.switchMu.Unlock()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		_ = cli.Close()
+		return ErrClosed
+	}
+	ep.cli = cli
+	return nil
+}
+
+// client 返回地址的客户端，尚未建立连接时返回nil
+func (f *failoverClient) client(ep *endpointState) *client {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return ep.cli
+}
+
+// probe 检查地址是否健康，尚未建立连接时先建立连接
+func (f *failoverClient) probe(ep *endpointState) error {
+	ctx, cancel := context.WithTimeout(context.Background(), f.policy.HealthCheckTimeout)
+	defer cancel()
+	cli := f.client(ep)
+	if cli == nil {
+		if err := f.connect(ctx, ep); err != nil {
+			return err
+		}
+		cli = f.client(ep)
+	}
+	return cli.probe(ctx)
+}
+
+// run 定期检查所有地址，并处理请求失败触发的切换
+func (f *failoverClient) run() {
+	defer close(f.done)
+	ticker := time.NewTicker(f.policy.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-ticker.C:
+			f.check()
+		case err := <-f.trigger:
+			f.failover(err)
+		}
+	}
+}
+
+// check 并发检查所有地址，当前地址失败达到阈值时故障转移，更高优先级的地址持续健康时切回
+func (f *failoverClient) check() {
+	errs := make([]error, len(f.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range f.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = f.probe(ep)
+		}()
+	}
+	wg.Wait()
+
+	now := time.Now()
+	for i, ep := range f.endpoints {
+		if errs[i] != nil {
+			ep.failures.Add(1)
+			ep.healthySince = time.Time{}
+			continue
+		}
+		ep.failures.Store(0)
+		if ep.healthySince.IsZero() {
+			ep.healthySince = now
+		}
+	}
+
+	f.mu.RLock()
+	active := f.active
+	f.mu.RUnlock()
+	if int(f.endpoints[active].failures.Load()) >= f.policy.FailureThreshold {
+		f.failover(errs[active])
+		return
+	}
+	for i := 0; i < active; i++ {
+		ep := f.endpoints[i]
+		if !ep.healthySince.IsZero() && now.Sub(ep.healthySince) >= f.policy.FailbackDelay {
+			if err := f.switchTo(i, true, nil); err != nil {
+				f.logger.Warn("failed to fail back milvus endpoint", slog.String("address", ep.Address), slog.Any("error", err))
+				continue
+			}
+			return
+		}
+	}
+}
+
+// failover 当前地址失败达到阈值时，按优先级切换到第一个健康的地址
+// cause: 触发切换的错误
+func (f *failoverClient) failover(cause error) {
+	f.mu.RLock()
+	active := f.active
+	f.mu.RUnlock()
+	if int(f.endpoints[active].failures.Load()) < f.policy.FailureThreshold {
+		// 触发后当前地址已恢复
+		return
+	}
+
+	for i, ep := range f.endpoints {
+		if i == active {
+			continue
+		}
+		if err := f.probe(ep); err != nil {
+			ep.failures.Add(1)
+			ep.healthySince = time.Time{}
+			continue
+		}
+		ep.failures.Store(0)
+		if ep.healthySince.IsZero() {
+			ep.healthySince = time.Now()
+		}
+		if err := f.switchTo(i, false, cause); err != nil {
+			f.logger.Warn("failed to fail over milvus endpoint", slog.String("address", ep.Address), slog.Any("error", err))
+			continue
+		}
+		return
+	}
+	f.logger.Error("no healthy milvus endpoint available",
+		slog.String("address", f.endpoints[active].Address), slog.Any("error", cause))
+}
+
+// switchTo 将当前地址切换为第i个地址，先同步数据库，再通知切换事件
+// failback: 是否为恢复后切回更高优先级的地址
+// cause: 故障转移的原因，切回时为nil
+func (f *failoverClient) switchTo(i int, failback bool, cause error) error {
+	f.switchMu.Lock()
+	ep := f.endpoints[i]
+	cli := f.client(ep)
+	if ep.database != f.database {
+		ctx, cancel := context.WithTimeout(context.Background(), f.policy.HealthCheckTimeout)
+		err := cli.UseDatabase(ctx, f.database)
+		cancel()
+		if err != nil {
+			f.switchMu.Unlock()
+			return errors.Wrapf(err, "failed to use database %s", f.database)
+		}
+		ep.database = f.database
+	}
+
+	f.mu.Lock()
+	from := f.endpoints[f.active]
+	f.active = i
+	f.mu.Unlock()
+	f.switchMu.Unlock()
+
+	event := FailoverEvent{From: from.Address, To: ep.Address, Failback: failback, Err: cause, Time: time.Now()}
+	if event.Failback {
+		f.logger.Info("milvus endpoint failed back", slog.String("from", event.From), slog.String("to", event.To))
+	} else {
+		f.logger.Warn("milvus endpoint failed over", slog.String("from", event.From), slog.String("to", event.To), slog.Any("error", cause))
+	}
+	if f.policy.OnSwitch != nil {
+		f.policy.OnSwitch(event)
+	}
+	return nil
+}
+
+// do 在当前地址上执行请求，服务不可用、超时或熔断的错误计入当前地址的连续失败次数
+// 只计入客户端自身超时设置触发的超时，调用方ctx的截止时间到达导致的超时不计入，避免调用方较短的截止时间使健康的地址被切换
+// ctx: 调用方传给Client方法的上下文
+func (f *failoverClient) do(ctx context.Context, fn func(cli Client) error) error {
+	f.mu.RLock()
+	if f.closed {
+		f.mu.RUnlock()
+		return ErrClosed
+	}
+	ep := f.endpoints[f.active]
+	cli := ep.cli
+	f.mu.RUnlock()
+
+	err := fn(cli)
+	switch {
+	case err == nil:
+		ep.failures.Store(0)
+	case errors.Is(err, ErrUnavailable) || errors.Is(err, ErrCircuitOpen) || (errors.Is(err, ErrTimeout) && ctx.Err() == nil):
+		if int(ep.failures.Add(1)) >= f.policy.FailureThreshold {
+			select {
+			case f.trigger <- err:
+			default:
+			}
+		}
+	}
+	return err
+}
+
+// address 返回当前地址
+func (f *failoverClient) address() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.endpoints[f.active].Address
+}
+
+// GetClient 返回当前地址的原始Milvus客户端，客户端已关闭时返回nil
+// 地址切换后之前返回的原始客户端仍然指向旧地址
+func (f *failoverClient) GetClient() *milvusclient.Client {
+	var raw *milvusclient.Client
+	_ = f.do(context.Background(), func(cli Client) error {
+		raw = cli.GetClient()
+		return nil
+	})
+	return raw
+}
+
+// CreateDatabase 创建数据库
+func (f *failoverClient) CreateDatabase(ctx context.Context, dbName string) error {
+	return f.do(ctx, func(cli Client) error { return cli.CreateDatabase(ctx, dbName) })
+}
+
+// DropDatabase 删除数据库
+func (f *failoverClient) DropDatabase(ctx context.Context, dbName string) error {
+	return f.do(ctx, func(cli Client) error { return cli.DropDatabase(ctx, dbName) })
+}
+
+// UseDatabase 切换当前地址的数据库，其他地址在切换到该地址时同步
+func (f *failoverClient) UseDatabase(ctx context.Context, dbName string) error {
+	f.switchMu.Lock()
+	defer f.switchMu.Unlock()
+
+	f.mu.RLock()
+	ep := f.endpoints[f.active]
+	f.mu.RUnlock()
+	err := f.do(ctx, func(cli Client) error { return cli.UseDatabase(ctx, dbName) })
+	if err != nil {
+		return err
+	}
+	ep.database = dbName
+	f.database = dbName
+	return nil
+}
+
+// ListDatabases 列出所有数据库
+func (f *failoverClient) ListDatabases(ctx context.Context) (dbs []string, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		dbs, err = cli.ListDatabases(ctx)
+		return err
+	})
+	return dbs, err
+}
+
+// CreateCollection 创建集合
+func (f *failoverClient) CreateCollection(ctx context.Context, schema *entity.Schema, shardNum int32) error {
+	return f.do(ctx, func(cli Client) error { return cli.CreateCollection(ctx, schema, shardNum) })
+}
+
+// CreateCollectionWithOptions 使用选项创建集合
+func (f *failoverClient) CreateCollectionWithOptions(ctx context.Context, schema *entity.Schema, opts ...CollectionOption) error {
+	return f.do(ctx, func(cli Client) error { return cli.CreateCollectionWithOptions(ctx, schema, opts...) })
+}
+
+// DropCollection 删除集合
+func (f *failoverClient) DropCollection(ctx context.Context, collectionName string) error {
+	return f.do(ctx, func(cli Client) error { return cli.DropCollection(ctx, collectionName) })
+}
+
+// HasCollection 检查集合是否存在
+func (f *failoverClient) HasCollection(ctx context.Context, collectionName string) (has bool, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		has, err = cli.HasCollection(ctx, collectionName)
+		return err
+	})
+	return has, err
+}
+
+// LoadCollection 加载集合到内存
+func (f *failoverClient) LoadCollection(ctx context.Context, collectionName string) error {
+	return f.do(ctx, func(cli Client) error { return cli.LoadCollection(ctx, collectionName) })
+}
+
+// ReleaseCollection 从内存中释放集合
+func (f *failoverClient) ReleaseCollection(ctx context.Context, collectionName string) error {
+	return f.do(ctx, func(cli Client) error { return cli.ReleaseCollection(ctx, collectionName) })
+}
+
+// GetCollectionStatistics 获取集合统计信息
+func (f *failoverClient) GetCollectionStatistics(ctx context.Context, collectionName string) (stats map[string]string, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		stats, err = cli.GetCollectionStatistics(ctx, collectionName)
+		return err
+	})
+	return stats, err
+}
+
+// DescribeCollection 获取集合详细信息
+func (f *failoverClient) DescribeCollection(ctx context.Context, collectionName string) (coll *entity.Collection, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		coll, err = cli.DescribeCollection(ctx, collectionName)
+		return err
+	})
+	return coll, err
+}
+
+// ListCollections 列出所有集合
+func (f *failoverClient) ListCollections(ctx context.Context) (names []string, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		names, err = cli.ListCollections(ctx)
+		return err
+	})
+	return names, err
+}
+
+// CreateAlias 创建集合别名
+func (f *failoverClient) CreateAlias(ctx context.Context, collectionName string, alias string) error {
+	return f.do(ctx, func(cli Client) error { return cli.CreateAlias(ctx, collectionName, alias) })
+}
+
+// DropAlias 删除集合别名
+func (f *failoverClient) DropAlias(ctx context.Context, alias string) error {
+	return f.do(ctx, func(cli Client) error { return cli.DropAlias(ctx, alias) })
+}
+
+// AlterAlias 修改别名指向的集合
+func (f *failoverClient) AlterAlias(ctx context.Context, collectionName string, alias string) error {
+	return f.do(ctx, func(cli Client) error { return cli.AlterAlias(ctx, collectionName, alias) })
+}
+
+// ListAliases 列出集合的所有别名
+func (f *failoverClient) ListAliases(ctx context.Context, collectionName string) (aliases []string, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		aliases, err = cli.ListAliases(ctx, collectionName)
+		return err
+	})
+	return aliases, err
+}
+
+// CreatePartition 创建分区
+func (f *failoverClient) CreatePartition(ctx context.Context, collectionName string, partitionName string) error {
+	return f.do(ctx, func(cli Client) error { return cli.CreatePartition(ctx, collectionName, partitionName) })
+}
+
+// DropPartition 删除分区
+func (f *failoverClient) DropPartition(ctx context.Context, collectionName string, partitionName string) error {
+	return f.do(ctx, func(cli Client) error { return cli.DropPartition(ctx, collectionName, partitionName) })
+}
+
+// HasPartition 检查分区是否存在
+func (f *failoverClient) HasPartition(ctx context.Context, collectionName string, partitionName string) (has bool, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		has, err = cli.HasPartition(ctx, collectionName, partitionName)
+		return err
+	})
+	return has, err
+}
+
+// LoadPartitions 加载分区到内存
+func (f *failoverClient) LoadPartitions(ctx context.Context, collectionName string, partitionNames []string) error {
+	return f.do(ctx, func(cli Client) error { return cli.LoadPartitions(ctx, collectionName, partitionNames) })
+}
+
+// ReleasePartitions 从内存中释放分区
+func (f *failoverClient) ReleasePartitions(ctx context.Context, collectionName string, partitionNames []string) error {
+	return f.do(ctx, func(cli Client) error { return cli.ReleasePartitions(ctx, collectionName, partitionNames) })
+}
+
+// ListPartitions 列出集合的所有分区
+func (f *failoverClient) ListPartitions(ctx context.Context, collectionName string) (names []string, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		names, err = cli.ListPartitions(ctx, collectionName)
+		return err
+	})
+	return names, err
+}
+
+// CreateIndex 创建索引
+func (f *failoverClient) CreateIndex(ctx context.Context, collectionName string, fieldName string, idx index.Index) error {
+	return f.do(ctx, func(cli Client) error { return cli.CreateIndex(ctx, collectionName, fieldName, idx) })
+}
+
+// DropIndex 删除索引
+func (f *failoverClient) DropIndex(ctx context.Context, collectionName string, fieldName string) error {
+	return f.do(ctx, func(cli Client) error { return cli.DropIndex(ctx, collectionName, fieldName) })
+}
+
+// DescribeIndex 获取索引信息
+func (f *failoverClient) DescribeIndex(ctx context.Context, collectionName string, fieldName string) (idx index.Index, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		idx, err = cli.DescribeIndex(ctx, collectionName, fieldName)
+		return err
+	})
+	return idx, err
+}
+
+// Insert 插入数据
+func (f *failoverClient) Insert(ctx context.Context, collectionName string, partitionName string, columns ...column.Column) (ids column.Column, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		ids, err = cli.Insert(ctx, collectionName, partitionName, columns...)
+		return err
+	})
+	return ids, err
+}
+
+// Delete 删除数据
+func (f *failoverClient) Delete(ctx context.Context, collectionName string, partitionName string, expr string) error {
+	return f.do(ctx, func(cli Client) error { return cli.Delete(ctx, collectionName, partitionName, expr) })
+}
+
+// Search 向量搜索
+func (f *failoverClient) Search(ctx context.Context, collectionName string, partitionNames []string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, expr string, params map[string]string) (results []milvusclient.ResultSet, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		results, err = cli.Search(ctx, collectionName, partitionNames, outputFields, vectors, vectorField, metricType, topK, expr, params)
+		return err
+	})
+	return results, err
+}
+
+// Query 标量查询
+func (f *failoverClient) Query(ctx context.Context, collectionName string, partitionNames []string, expr string, outputFields []string) (columns []column.Column, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		columns, err = cli.Query(ctx, collectionName, partitionNames, expr, outputFields)
+		return err
+	})
+	return columns, err
+}
+
+// QueryWithLimit 带数量限制的标量查询
+func (f *failoverClient) QueryWithLimit(ctx context.Context, collectionName string, partitionNames []string, expr string, outputFields []string, limit int) (columns []column.Column, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		columns, err = cli.QueryWithLimit(ctx, collectionName, partitionNames, expr, outputFields, limit)
+		return err
+	})
+	return columns, err
+}
+
+// Compact 压缩集合
+func (f *failoverClient) Compact(ctx context.Context, collectionName string) (compactionID int64, err error) {
+	err = f.do(ctx, func(cli Client) error {
+		compactionID, err = cli.Compact(ctx, collectionName)
+		return err
+	})
+	return compactionID, err
+}
+
+// CheckHealth 检查当前地址的健康状态
+func (f *failoverClient) CheckHealth(ctx context.Context) error {
+	return f.do(ctx, func(cli Client) error { return cli.CheckHealth(ctx) })
+}
+
+// Close 停止健康检查并关闭所有地址的客户端
+func (f *failoverClient) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	f.mu.Unlock()
+
+	close(f.stop)
+	<-f.done
+
+	var firstErr error
+	for _, ep := range f.endpoints {
+		if ep.cli == nil {
+			continue
+		}
+		if err := ep.cli.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
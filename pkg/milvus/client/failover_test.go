@@ -0,0 +1,346 @@
+package client
+
+import (
+	"context"
+	"net"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// fakeMilvus 可以切换健康状态的gRPC服务，CheckHealth按状态返回，其他方法返回Unimplemented
+type fakeMilvus struct {
+	mu    sync.Mutex
+	down  bool
+	hang  map[string]bool // 不响应的方法名，请求阻塞到调用方取消
+	calls []string        // 收到的请求，格式为"方法名@数据库"
+}
+
+// setDown 设置服务是否报告不健康
+func (s *fakeMilvus) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+// setHang 设置方法是否不响应
+func (s *fakeMilvus) setHang(method string, hang bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.hang == nil {
+		s.hang = make(map[string]bool)
+	}
+	s.hang[method] = hang
+}
+
+// received 返回收到的请求
+func (s *fakeMilvus) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+// handle 处理所有方法的请求
+func (s *fakeMilvus) handle(_ any, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	db := ""
+	if values := md.Get("dbname"); len(values) > 0 {
+		db = values[0]
+	}
+
+	s.mu.Lock()
+	down := s.down
+	hang := s.hang[path.Base(method)]
+	s.calls = append(s.calls, path.Base(method)+"@"+db)
+	s.mu.Unlock()
+
+	if hang {
+		<-stream.Context().Done()
+		return status.FromContextError(stream.Context().Err()).Err()
+	}
+	if !strings.HasSuffix(method, "/CheckHealth") {
+		return status.Error(codes.Unimplemented, "not implemented")
+	}
+	if err := stream.RecvMsg(&milvuspb.CheckHealthRequest{}); err != nil {
+		return err
+	}
+	return stream.SendMsg(&milvuspb.CheckHealthResponse{IsHealthy: !down, Reasons: []string{"query node down"}})
+}
+
+// startFakeMilvus 启动可以切换健康状态的gRPC服务
+func startFakeMilvus(t *testing.T) (*fakeMilvus, string) {
+	srv := &fakeMilvus{}
+	return srv, testserver.Start(t, testserver.WithHandler(srv.handle))
+}
+
+// unreachableAddress 返回没有服务监听的地址
+func unreachableAddress(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+	return addr
+}
+
+// newFailoverTestClient 创建连接primary与secondary两个地址的客户端，返回客户端与切换事件
+func newFailoverTestClient(t *testing.T, policy *FailoverPolicy, primary, secondary string) (*failoverClient, chan FailoverEvent) {
+	events := make(chan FailoverEvent, 10)
+	policy.OnSwitch = func(e FailoverEvent) { events <- e }
+	cli, err := NewWithOptions(context.Background(),
+		WithEndpoints(Endpoint{Address: secondary, Priority: 1}, Endpoint{Address: primary, Priority: 0}),
+		WithFailover(policy), WithRetryPolicy(OpRead, nil))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cli.Close() })
+	return cli.(*failoverClient), events
+}
+
+// waitEvent 等待一次切换事件
+func waitEvent(t *testing.T, events chan FailoverEvent) FailoverEvent {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for failover event")
+		return FailoverEvent{}
+	}
+}
+
+// TestFailover 测试多地址故障转移
+func TestFailover(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("连接优先级最高的地址", func(t *testing.T) {
+		primary, primaryAddr := startFakeMilvus(t)
+		_, secondaryAddr := startFakeMilvus(t)
+		policy := DefaultFailoverPolicy()
+		cli, _ := newFailoverTestClient(t, policy, primaryAddr, secondaryAddr)
+
+		assert.Equal(t, primaryAddr, cli.address())
+		require.NoError(t, cli.CheckHealth(ctx))
+		assert.Contains(t, primary.received(), "CheckHealth@default")
+	})
+
+	t.Run("请求返回服务不可用时切换", func(t *testing.T) {
+		primary, primaryAddr := startFakeMilvus(t)
+		secondary, secondaryAddr := startFakeMilvus(t)
+		policy := DefaultFailoverPolicy()
+		policy.HealthCheckInterval = time.Hour
+		policy.FailureThreshold = 2
+		cli, events := newFailoverTestClient(t, policy, primaryAddr, secondaryAddr)
+
+		primary.setDown(true)
+		assert.ErrorIs(t, cli.CheckHealth(ctx), ErrUnavailable)
+		assert.Equal(t, primaryAddr, cli.address(), "未达到失败阈值时不切换")
+		assert.ErrorIs(t, cli.CheckHealth(ctx), ErrUnavailable)
+
+		e := waitEvent(t, events)
+		assert.Equal(t, primaryAddr, e.From)
+		assert.Equal(t, secondaryAddr, e.To)
+		assert.False(t, e.Failback)
+		assert.ErrorIs(t, e.Err, ErrUnavailable)
+		assert.Equal(t, secondaryAddr, cli.address())
+
+		require.NoError(t, cli.CheckHealth(ctx))
+		assert.Contains(t, secondary.received(), "CheckHealth@default")
+	})
+
+	t.Run("调用方截止时间到达时不切换", func(t *testing.T) {
+		primary, primaryAddr := startFakeMilvus(t)
+		_, secondaryAddr := startFakeMilvus(t)
+		policy := DefaultFailoverPolicy()
+		policy.HealthCheckInterval = time.Hour
+		policy.FailureThreshold = 2
+		cli, events := newFailoverTestClient(t, policy, primaryAddr, secondaryAddr)
+
+		primary.setHang("CheckHealth", true)
+		for range 3 {
+			reqCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+			assert.ErrorIs(t, cli.CheckHealth(reqCtx), ErrTimeout)
+			cancel()
+		}
+
+		select {
+		case e := <-events:
+			assert.Fail(t, "unexpected failover", "%+v", e)
+		case <-time.After(200 * time.Millisecond):
+		}
+		assert.Equal(t, primaryAddr, cli.address())
+	})
+
+	t.Run("客户端超时时切换", func(t *testing.T) {
+		primary, primaryAddr := startFakeMilvus(t)
+		_, secondaryAddr := startFakeMilvus(t)
+		policy := DefaultFailoverPolicy()
+		policy.HealthCheckInterval = time.Hour
+		policy.FailureThreshold = 2
+		cli, events := newFailoverTestClient(t, policy, primaryAddr, secondaryAddr)
+
+		primary.setHang("CheckHealth", true)
+		for range 2 {
+			assert.ErrorIs(t, cli.CheckHealth(ContextWithTimeout(ctx, 100*time.Millisecond)), ErrTimeout)
+		}
+
+		e := waitEvent(t, events)
+		assert.Equal(t, secondaryAddr, e.To)
+		assert.ErrorIs(t, e.Err, ErrTimeout)
+		assert.Equal(t, secondaryAddr, cli.address())
+	})
+
+	t.Run("健康检查失败时切换并在恢复后切回", func(t *testing.T) {
+		primary, primaryAddr := startFakeMilvus(t)
+		_, secondaryAddr := startFakeMilvus(t)
+		policy := DefaultFailoverPolicy()
+		policy.HealthCheckInterval = 20 * time.Millisecond
+		policy.FailureThreshold = 2
+		policy.FailbackDelay = 200 * time.Millisecond
+		cli, events := newFailoverTestClient(t, policy, primaryAddr, secondaryAddr)
+
+		primary.setDown(true)
+		e := waitEvent(t, events)
+		assert.Equal(t, secondaryAddr, e.To)
+		assert.False(t, e.Failback)
+
+		recovered := time.Now()
+		primary.setDown(false)
+		e = waitEvent(t, events)
+		assert.Equal(t, secondaryAddr, e.From)
+		assert.Equal(t, primaryAddr, e.To)
+		assert.True(t, e.Failback)
+		assert.NoError(t, e.Err)
+		assert.GreaterOrEqual(t, e.Time.Sub(recovered), policy.FailbackDelay, "持续健康FailbackDelay后才切回")
+		assert.Equal(t, primaryAddr, cli.address())
+	})
+
+	t.Run("没有健康的地址时不切换", func(t *testing.T) {
+		primary, primaryAddr := startFakeMilvus(t)
+		secondary, secondaryAddr := startFakeMilvus(t)
+		policy := DefaultFailoverPolicy()
+		policy.HealthCheckInterval = time.Hour
+		policy.FailureThreshold = 1
+		cli, events := newFailoverTestClient(t, policy, primaryAddr, secondaryAddr)
+
+		primary.setDown(true)
+		secondary.setDown(true)
+		assert.ErrorIs(t, cli.CheckHealth(ctx), ErrUnavailable)
+		require.Eventually(t, func() bool { return len(secondary.received()) > 0 }, 5*time.Second, 10*time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		assert.Empty(t, events)
+		assert.Equal(t, primaryAddr, cli.address())
+	})
+
+	t.Run("切换后使用当前数据库", func(t *testing.T) {
+		primary, primaryAddr := startFakeMilvus(t)
+		secondary, secondaryAddr := startFakeMilvus(t)
+		policy := DefaultFailoverPolicy()
+		policy.HealthCheckInterval = 20 * time.Millisecond
+		policy.FailureThreshold = 1
+		cli, events := newFailoverTestClient(t, policy, primaryAddr, secondaryAddr)
+
+		// 等待健康检查连接备用地址后再切换数据库
+		require.Eventually(t, func() bool { return len(secondary.received()) > 0 }, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, cli.UseDatabase(ctx, "analytics"))
+
+		primary.setDown(true)
+		waitEvent(t, events)
+		require.NoError(t, cli.CheckHealth(ctx))
+		calls := secondary.received()
+		assert.Contains(t, calls, "Connect@analytics", "切换前同步数据库")
+		assert.Equal(t, "CheckHealth@analytics", calls[len(calls)-1])
+	})
+
+	t.Run("同步数据库超时时不切换", func(t *testing.T) {
+		primary, primaryAddr := startFakeMilvus(t)
+		secondary, secondaryAddr := startFakeMilvus(t)
+		policy := DefaultFailoverPolicy()
+		policy.HealthCheckInterval = 20 * time.Millisecond
+		policy.HealthCheckTimeout = 200 * time.Millisecond
+		policy.FailureThreshold = 1
+		cli, events := newFailoverTestClient(t, policy, primaryAddr, secondaryAddr)
+
+		require.Eventually(t, func() bool { return len(secondary.received()) > 0 }, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, cli.UseDatabase(ctx, "analytics"))
+
+		secondary.setHang("Connect", true)
+		primary.setDown(true)
+		require.Eventually(t, func() bool {
+			return slices.Contains(secondary.received(), "Connect@analytics")
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Empty(t, events)
+		assert.Equal(t, primaryAddr, cli.address())
+
+		// 同步超时后继续健康检查，备用地址恢复响应时完成切换
+		secondary.setHang("Connect", false)
+		e := waitEvent(t, events)
+		assert.Equal(t, secondaryAddr, e.To)
+		assert.Equal(t, secondaryAddr, cli.address())
+	})
+
+	t.Run("跳过无法连接的地址", func(t *testing.T) {
+		_, addrA := startFakeMilvus(t)
+		_, addrB := startFakeMilvus(t)
+		policy := DefaultFailoverPolicy()
+		policy.HealthCheckTimeout = 200 * time.Millisecond
+		cli, err := NewWithOptions(ctx,
+			WithEndpoints(Endpoint{Address: addrB, Priority: 2}, Endpoint{Address: unreachableAddress(t)}, Endpoint{Address: addrA, Priority: 1}),
+			WithFailover(policy))
+		require.NoError(t, err)
+		defer cli.Close()
+		assert.Equal(t, addrA, cli.(*failoverClient).address())
+	})
+
+	t.Run("所有地址都无法连接", func(t *testing.T) {
+		policy := DefaultFailoverPolicy()
+		policy.HealthCheckTimeout = 100 * time.Millisecond
+		addrA, addrB := unreachableAddress(t), unreachableAddress(t)
+		cli, err := NewWithOptions(ctx, WithEndpoints(Endpoint{Address: addrA}, Endpoint{Address: addrB}), WithFailover(policy))
+		require.Error(t, err)
+		assert.Nil(t, cli)
+		assert.ErrorContains(t, err, "failed to connect to any milvus endpoint")
+		assert.ErrorContains(t, err, addrA)
+		assert.ErrorContains(t, err, addrB)
+	})
+
+	t.Run("关闭客户端", func(t *testing.T) {
+		_, primaryAddr := startFakeMilvus(t)
+		_, secondaryAddr := startFakeMilvus(t)
+		cli, _ := newFailoverTestClient(t, DefaultFailoverPolicy(), primaryAddr, secondaryAddr)
+		require.NoError(t, cli.Close())
+		assert.ErrorIs(t, cli.CheckHealth(ctx), ErrClosed)
+		assert.Nil(t, cli.GetClient())
+		assert.NoError(t, cli.Close(), "重复关闭")
+	})
+
+	t.Run("无效配置", func(t *testing.T) {
+		_, addr := startFakeMilvus(t)
+		_, err := NewWithOptions(ctx, WithEndpoints(Endpoint{Address: ""}))
+		assert.ErrorContains(t, err, "address must not be empty")
+		_, err = NewWithOptions(ctx, WithEndpoints(Endpoint{Address: addr}, Endpoint{Address: addr, Priority: 1}))
+		assert.ErrorContains(t, err, "duplicate failover endpoint")
+	})
+
+	t.Run("未配置的策略字段使用默认值", func(t *testing.T) {
+		_, addr := startFakeMilvus(t)
+		cli, err := NewWithOptions(ctx, WithEndpoints(Endpoint{Address: addr}), WithFailover(&FailoverPolicy{FailureThreshold: 5}))
+		require.NoError(t, err)
+		defer cli.Close()
+		policy := cli.(*failoverClient).policy
+		assert.Equal(t, 5, policy.FailureThreshold)
+		assert.Equal(t, DefaultFailoverPolicy().HealthCheckInterval, policy.HealthCheckInterval)
+		assert.Equal(t, DefaultFailoverPolicy().HealthCheckTimeout, policy.HealthCheckTimeout)
+		assert.Zero(t, policy.FailbackDelay)
+	})
+}
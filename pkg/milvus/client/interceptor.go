@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+)
+
+// OperationInfo 描述一次客户端操作，供拦截器读取
+// Insert、Search超过请求大小上限被拆分时，每个分段是一次独立的操作
+type OperationInfo struct {
+	Name       string         // 操作名称，与Client接口方法名一致，例如"Insert"
+	Class      OperationClass // 操作类别
+	Collection string         // 集合名称，与集合无关的操作为空
+	Database   string         // 执行操作时客户端的当前数据库
+
+	// Args 参数摘要，只包含便于记录的小参数，不包含向量与列数据
+	// 可能的键：partition、partitions、field、alias、database、expr、outputFields、rows、nq、topK、limit、vectorField、metricType
+	Args map[string]any
+
+	// 以下字段在操作执行后填充
+	Attempts   int // 实际尝试次数，包含重试
+	ResultRows int // 结果行数：Insert为写入行数，Delete为删除行数，Query为返回行数，Search为所有查询的命中总数
+
+	// mayRetry 返回false时该操作不自动重试，nil表示允许重试
+	mayRetry func(ctx context.Context) bool
+}
+
+// Invoker 执行操作，拦截器调用它以继续执行后续拦截器与实际请求
+type Invoker func(ctx context.Context, info *OperationInfo) error
+
+// Interceptor 客户端操作拦截器，包裹每一次Client方法调用（包含重试）
+// 拦截器可以在调用invoker前后记录日志、指标，修改ctx，或直接返回错误以拒绝请求
+// 返回的错误会作为方法的错误返回给调用方
+type Interceptor func(ctx context.Context, info *OperationInfo, invoker Invoker) error
+
+// chainInterceptors 将多个拦截器合并为一个，第一个拦截器在最外层
+func chainInterceptors(interceptors []Interceptor) Interceptor {
+	switch len(interceptors) {
+	case 0:
+		return nil
+	case 1:
+		return interceptors[0]
+	}
+	return func(ctx context.Context, info *OperationInfo, invoker Invoker) error {
+		return interceptors[0](ctx, info, chainedInvoker(interceptors, 1, invoker))
+	}
+}
+
+// chainedInvoker 返回从第i个拦截器开始的调用链
+func chainedInvoker(interceptors []Interceptor, i int, final Invoker) Invoker {
+	if i == len(interceptors) {
+		return final
+	}
+	return func(ctx context.Context, info *OperationInfo) error {
+		return interceptors[i](ctx, info, chainedInvoker(interceptors, i+1, final))
+	}
+}
+
+// buildInterceptor 按配置组合内置拦截器与用户拦截器，内置拦截器位于外层
+func buildInterceptor(options *Options) (Interceptor, error) {
+	var interceptors []Interceptor
+	if options.TracerProvider != nil {
+		interceptors = append(interceptors, tracingInterceptor(options.TracerProvider))
+	}
+	var metrics *clientMetrics
+	name := options.MetricsName
+	if name == "" {
+		name = options.Address
+	}
+	if options.MetricsRegisterer != nil {
+		var err error
+		if metrics, err = newClientMetrics(options.MetricsRegisterer, options.MetricsLabels); err != nil {
+			return nil, err
+		}
+		interceptors = append(interceptors, metrics.interceptor(name))
+	}
+	if options.Logger != nil && options.SlowThreshold > 0 {
+		interceptors = append(interceptors, loggingInterceptor(options.Logger, options.SlowThreshold))
+	}
+	if options.CircuitBreaker != nil {
+		var onChange []func(from, to BreakerState)
+		if options.Logger != nil {
+			onChange = append(onChange, logBreakerStateChange(options.Logger, options.Address))
+		}
+		if metrics != nil {
+			onChange = append(onChange, metrics.breakerStateChange(name))
+		}
+		interceptors = append(interceptors, newCircuitBreaker(options.CircuitBreaker, onChange...).interceptor())
+	}
+	if len(options.RateLimits) > 0 {
+		interceptors = append(interceptors, rateLimitInterceptor(options.RateLimits, metrics, name))
+	}
+	interceptors = append(interceptors, options.Interceptors...)
+	return chainInterceptors(interceptors), nil
+}
+
+// invoke 执行一次操作：设置超时后依次经过拦截器，再按重试策略调用fn
+func (c *client) invoke(ctx context.Context, op *OperationInfo, fn func(ctx context.Context) error) error {
+	if op.Database == "" {
+		op.Database = c.currentDatabase()
+	}
+	ctx, cancel := c.withTimeout(ctx, op.Class)
+	defer cancel()
+	if c.interceptor == nil {
+		return c.retry(ctx, op, fn)
+	}
+	return c.interceptor(ctx, op, func(ctx context.Context, op *OperationInfo) error {
+		return c.retry(ctx, op, fn)
+	})
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// TestInterceptors 测试客户端操作拦截器
+func TestInterceptors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("执行顺序", func(t *testing.T) {
+		var order []string
+		record := func(name string) Interceptor {
+			return func(ctx context.Context, info *OperationInfo, invoker Invoker) error {
+				order = append(order, name+":before")
+				err := invoker(ctx, info)
+				order = append(order, name+":after")
+				return err
+			}
+		}
+		c := newRetryTestClient(WithInterceptors(record("a"), record("b")), WithInterceptors(record("c")))
+
+		err := c.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			order = append(order, "call")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a:before", "b:before", "c:before", "call", "c:after", "b:after", "a:after"}, order)
+	})
+
+	t.Run("包含重试与错误转换", func(t *testing.T) {
+		var got *OperationInfo
+		var gotErr error
+		c := newRetryTestClient(
+			WithRetryPolicy(OpRead, fastPolicy(3)),
+			WithInterceptors(func(ctx context.Context, info *OperationInfo, invoker Invoker) error {
+				gotErr = invoker(ctx, info)
+				got = info
+				return gotErr
+			}),
+		)
+
+		err := c.invoke(ctx, &OperationInfo{Name: "Search", Class: OpRead, Collection: "c1"}, func(ctx context.Context) error {
+			return merr.ErrServiceUnavailable
+		})
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.ErrorIs(t, gotErr, ErrUnavailable)
+		require.NotNil(t, got)
+		assert.Equal(t, "c1", got.Collection)
+		assert.Equal(t, 3, got.Attempts)
+	})
+
+	t.Run("拒绝请求", func(t *testing.T) {
+		rejected := errors.New("rejected")
+		c := newRetryTestClient(WithInterceptors(func(ctx context.Context, info *OperationInfo, invoker Invoker) error {
+			return rejected
+		}))
+
+		called := false
+		err := c.invoke(ctx, &OperationInfo{Name: "Insert", Class: OpWrite}, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		assert.Equal(t, rejected, err)
+		assert.False(t, called)
+	})
+}
+
+// TestGrpcInterceptors 测试追加到gRPC连接的拦截器与客户端操作拦截器
+func TestGrpcInterceptors(t *testing.T) {
+	addr := testserver.Start(t)
+
+	var mu sync.Mutex
+	var methods []string
+	var infos []OperationInfo
+	connectCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cli, err := NewWithOptions(connectCtx,
+		WithAddress(addr),
+		WithDisableConn(true),
+		WithRetryPolicy(OpRead, nil),
+		WithUnaryInterceptors(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			mu.Lock()
+			methods = append(methods, method)
+			mu.Unlock()
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+		WithInterceptors(func(ctx context.Context, info *OperationInfo, invoker Invoker) error {
+			err := invoker(ctx, info)
+			mu.Lock()
+			infos = append(infos, *info)
+			mu.Unlock()
+			return err
+		}),
+	)
+	require.NoError(t, err)
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = cli.HasPartition(ctx, "c1", "p1")
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, methods)
+	assert.Contains(t, methods[len(methods)-1], "HasPartition")
+	require.Len(t, infos, 1)
+	assert.Equal(t, "HasPartition", infos[0].Name)
+	assert.Equal(t, "c1", infos[0].Collection)
+	assert.Equal(t, map[string]any{"partition": "p1"}, infos[0].Args)
+}
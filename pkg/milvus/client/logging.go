@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"google.golang.org/grpc/stats"
+)
+
+// redacted 替换敏感信息的占位符
+const redacted = "[REDACTED]"
+
+// newLogger 返回配置的日志记录器，未配置时返回丢弃所有日志的记录器
+func newLogger(options *Options) *slog.Logger {
+	if options.Logger != nil {
+		return options.Logger
+	}
+	return slog.New(slog.DiscardHandler)
+}
+
+// LogValue 实现slog.LogValuer，记录配置时隐藏密码与APIKey
+func (o Options) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("address", o.Address),
+		slog.String("database", o.DBName),
+		slog.Bool("tls", o.EnableTLSAuth || o.TLS != nil),
+	}
+	if len(o.Endpoints) > 0 {
+		addresses := make([]string, len(o.Endpoints))
+		for i, ep := range o.Endpoints {
+			addresses[i] = ep.Address
+		}
+		attrs[0] = slog.Any("endpoints", addresses)
+	}
+	if o.Username != "" {
+		attrs = append(attrs, slog.String("username", o.Username))
+	}
+	if o.Password != "" {
+		attrs = append(attrs, slog.String("password", redacted))
+	}
+	if o.APIKey != "" {
+		attrs = append(attrs, slog.String("api_key", redacted))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// redactValue 将向量与列数据替换为摘要，避免在日志中输出数据内容
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case entity.Vector:
+		return fmt.Sprintf("<vector dim=%d>", v.Dim())
+	case []entity.Vector:
+		return fmt.Sprintf("<%d vectors>", len(v))
+	case column.Column:
+		return fmt.Sprintf("<column %s rows=%d>", v.Name(), v.Len())
+	case []column.Column:
+		return fmt.Sprintf("<%d columns rows=%d>", len(v), columnRows(v))
+	default:
+		return value
+	}
+}
+
+// logArgs 将操作参数转换为日志属性组
+func logArgs(args map[string]any) slog.Attr {
+	attrs := make([]any, 0, len(args))
+	for k, v := range args {
+		attrs = append(attrs, slog.Any(k, redactValue(v)))
+	}
+	return slog.Group("args", attrs...)
+}
+
+// loggingInterceptor 记录耗时超过threshold的操作
+func loggingInterceptor(logger *slog.Logger, threshold time.Duration) Interceptor {
+	return func(ctx context.Context, info *OperationInfo, invoker Invoker) error {
+		start := time.Now()
+		err := invoker(ctx, info)
+		if elapsed := time.Since(start); elapsed >= threshold {
+			attrs := []any{
+				slog.String("op", info.Name),
+				slog.String("collection", info.Collection),
+				slog.Duration("duration", elapsed),
+				slog.Int("attempts", info.Attempts),
+				slog.Int("result_rows", info.ResultRows),
+				logArgs(info.Args),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+			}
+			logger.WarnContext(ctx, "slow milvus operation", attrs...)
+		}
+		return err
+	}
+}
+
+// logBreakerStateChange 返回记录熔断器状态变化的回调
+func logBreakerStateChange(logger *slog.Logger, address string) func(from, to BreakerState) {
+	return func(from, to BreakerState) {
+		level := slog.LevelInfo
+		if to == BreakerOpen {
+			level = slog.LevelWarn
+		}
+		logger.Log(context.Background(), level, "milvus circuit breaker state changed",
+			slog.String("address", address),
+			slog.String("from", from.String()),
+			slog.String("to", to.String()),
+		)
+	}
+}
+
+// connLogger 通过gRPC连接事件记录连接建立、断开与重连
+type connLogger struct {
+	logger  *slog.Logger
+	address string
+	conns   atomic.Int64 // 已建立的连接次数
+	closing atomic.Bool  // 客户端正在关闭，此后的连接断开属于正常关闭
+}
+
+// TagRPC 实现stats.Handler
+func (l *connLogger) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+// HandleRPC 实现stats.Handler
+func (l *connLogger) HandleRPC(context.Context, stats.RPCStats) {}
+
+// TagConn 实现stats.Handler
+func (l *connLogger) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn 实现stats.Handler，记录连接的建立与断开
+func (l *connLogger) HandleConn(ctx context.Context, s stats.ConnStats) {
+	switch s.(type) {
+	case *stats.ConnBegin:
+		if l.conns.Add(1) == 1 {
+			l.logger.DebugContext(ctx, "milvus connection established", slog.String("address", l.address))
+		} else {
+			l.logger.InfoContext(ctx, "milvus reconnected", slog.String("address", l.address))
+		}
+	case *stats.ConnEnd:
+		if l.closing.Load() {
+			return
+		}
+		l.logger.WarnContext(ctx, "milvus connection lost", slog.String("address", l.address))
+	}
+}
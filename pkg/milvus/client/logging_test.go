@@ -0,0 +1,130 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/stats"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// newTestLogger 创建输出到缓冲区的日志记录器
+func newTestLogger() (*slog.Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})), buf
+}
+
+// TestLogging 测试日志记录
+func TestLogging(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("隐藏认证信息", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		options := DefaultOptions()
+		WithAddress("milvus:19530")(options)
+		WithAuth("root", "secret-password")(options)
+		logger.Info("options", slog.Any("options", options), slog.Any("value", *options))
+
+		assert.Contains(t, buf.String(), "username=root")
+		assert.Contains(t, buf.String(), "password="+redacted)
+		assert.NotContains(t, buf.String(), "secret-password")
+
+		buf.Reset()
+		WithAPIKey("secret-key")(options)
+		logger.Info("options", slog.Any("options", options))
+		assert.NotContains(t, buf.String(), "secret-key")
+	})
+
+	t.Run("隐藏向量数据", func(t *testing.T) {
+		vectors := []entity.Vector{entity.FloatVector{0.123, 0.456}, entity.FloatVector{0.789, 0.012}}
+		assert.Equal(t, "<vector dim=2>", redactValue(vectors[0]))
+		assert.Equal(t, "<2 vectors>", redactValue(vectors))
+		assert.Equal(t, "p1", redactValue("p1"))
+
+		logger, buf := newTestLogger()
+		logger.Info("op", logArgs(map[string]any{"vectors": vectors, "topK": 10}))
+		assert.Contains(t, buf.String(), "args.topK=10")
+		assert.NotContains(t, buf.String(), "0.123")
+	})
+
+	t.Run("慢操作", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		c := newRetryTestClient(WithLogger(logger), WithSlowThreshold(10*time.Millisecond))
+
+		require.NoError(t, c.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead, Collection: "c1"}, func(ctx context.Context) error {
+			return nil
+		}))
+		assert.Empty(t, buf.String())
+
+		require.NoError(t, c.invoke(ctx, &OperationInfo{Name: "Search", Class: OpRead, Collection: "c1", Args: map[string]any{"topK": 5}}, func(ctx context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		}))
+		assert.Contains(t, buf.String(), `level=WARN msg="slow milvus operation" op=Search collection=c1`)
+		assert.Contains(t, buf.String(), "args.topK=5")
+	})
+
+	t.Run("重试", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		c := newRetryTestClient(WithLogger(logger), WithRetryPolicy(OpRead, fastPolicy(2)))
+
+		_ = c.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead, Collection: "c1"}, func(ctx context.Context) error {
+			return merr.ErrServiceUnavailable
+		})
+		assert.Contains(t, buf.String(), `level=WARN msg="retrying milvus operation" op=Query collection=c1 attempt=1`)
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("retrying")))
+	})
+
+	t.Run("连接事件", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		l := &connLogger{logger: logger, address: "milvus:19530"}
+
+		l.HandleConn(ctx, &stats.ConnBegin{})
+		assert.Contains(t, buf.String(), "milvus connection established")
+		l.HandleConn(ctx, &stats.ConnEnd{})
+		assert.Contains(t, buf.String(), "level=WARN msg=\"milvus connection lost\" address=milvus:19530")
+		l.HandleConn(ctx, &stats.ConnBegin{})
+		assert.Contains(t, buf.String(), "level=INFO msg=\"milvus reconnected\" address=milvus:19530")
+
+		buf.Reset()
+		l.closing.Store(true)
+		l.HandleConn(ctx, &stats.ConnEnd{})
+		assert.Empty(t, buf.String())
+	})
+}
+
+// TestLoggingLifecycle 测试连接与关闭日志
+func TestLoggingLifecycle(t *testing.T) {
+	t.Run("连接与关闭", func(t *testing.T) {
+		addr := testserver.Start(t)
+		logger, buf := newTestLogger()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cli, err := NewWithOptions(ctx, WithAddress(addr), WithDisableConn(true), WithAuth("root", "secret-password"), WithLogger(logger))
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "milvus client connected")
+		require.NoError(t, cli.Close())
+		assert.Contains(t, buf.String(), "milvus client closed")
+		assert.NotContains(t, buf.String(), "connection lost")
+		assert.NotContains(t, buf.String(), "secret-password")
+	})
+
+	t.Run("连接失败", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		_, err := NewWithOptions(ctx, WithAddress("127.0.0.1:1"), WithLogger(logger))
+		require.Error(t, err)
+		assert.Contains(t, buf.String(), `level=ERROR msg="failed to connect to milvus"`)
+	})
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/status"
+)
+
+// errorKindNames 错误分类对应的指标标签值
+var errorKindNames = []struct {
+	kind error
+	name string
+}{
+	{ErrClosed, "closed"},
+	{ErrCollectionNotFound, "collection_not_found"},
+	{ErrPartitionNotFound, "partition_not_found"},
+	{ErrDatabaseNotFound, "database_not_found"},
+	{ErrIndexNotFound, "index_not_found"},
+	{ErrAliasNotFound, "alias_not_found"},
+	{ErrAlreadyExists, "already_exists"},
+	{ErrNotLoaded, "not_loaded"},
+	{ErrSchemaMismatch, "schema_mismatch"},
+	{ErrInvalidParameter, "invalid_parameter"},
+	{ErrRateLimited, "rate_limited"},
+	{ErrQuotaExceeded, "quota_exceeded"},
+	{ErrTimeout, "timeout"},
+	{ErrUnavailable, "unavailable"},
+	{ErrPermissionDenied, "permission_denied"},
+	{ErrUnsupported, "unsupported"},
+	{ErrCircuitOpen, "circuit_open"},
+	{context.Canceled, "canceled"},
+}
+
+// clientMetrics 客户端的Prometheus指标，同一Registerer上的多个客户端共享指标，通过client标签区分
+type clientMetrics struct {
+	duration     *prometheus.HistogramVec
+	errors       *prometheus.CounterVec
+	inFlight     *prometheus.GaugeVec
+	insertedRows *prometheus.CounterVec
+	returnedRows *prometheus.CounterVec
+
+	throttleWait    *prometheus.HistogramVec
+	throttleWaiting *prometheus.GaugeVec
+	circuitState    *prometheus.GaugeVec
+}
+
+// newClientMetrics 创建指标并注册到reg，已注册的同名且常量标签相同的指标直接复用
+// labels: 附加到所有指标的常量标签
+func newClientMetrics(reg prometheus.Registerer, labels prometheus.Labels) (*clientMetrics, error) {
+	m := &clientMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   "milvus",
+			Subsystem:   "client",
+			ConstLabels: labels,
+			Name:        "operation_duration_seconds",
+			Help:        "Latency of Milvus client operations, including retries.",
+			Buckets:     []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"client", "operation"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "milvus",
+			Subsystem:   "client",
+			ConstLabels: labels,
+			Name:        "operation_errors_total",
+			Help:        "Failed Milvus client operations by error code and kind.",
+		}, []string{"client", "operation", "code", "kind"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   "milvus",
+			Subsystem:   "client",
+			ConstLabels: labels,
+			Name:        "operations_in_flight",
+			Help:        "Milvus client operations currently in progress.",
+		}, []string{"client", "operation"}),
+		insertedRows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "milvus",
+			Subsystem:   "client",
+			ConstLabels: labels,
+			Name:        "inserted_rows_total",
+			Help:        "Rows inserted into Milvus collections.",
+		}, []string{"client", "collection"}),
+		returnedRows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "milvus",
+			Subsystem:   "client",
+			ConstLabels: labels,
+			Name:        "returned_rows_total",
+			Help:        "Rows returned by Milvus queries and searches.",
+		}, []string{"client", "collection", "operation"}),
+		throttleWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   "milvus",
+			Subsystem:   "client",
+			ConstLabels: labels,
+			Name:        "throttle_wait_seconds",
+			Help:        "Time Milvus client operations waited for client-side rate limits and concurrency caps.",
+			Buckets:     []float64{0, .001, .005, .01, .05, .1, .5, 1, 5, 10},
+		}, []string{"client", "class"}),
+		throttleWaiting: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   "milvus",
+			Subsystem:   "client",
+			ConstLabels: labels,
+			Name:        "throttle_waiting",
+			Help:        "Milvus client operations currently waiting for client-side rate limits and concurrency caps.",
+		}, []string{"client", "class"}),
+		circuitState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   "milvus",
+			Subsystem:   "client",
+			ConstLabels: labels,
+			Name:        "circuit_breaker_state",
+			Help:        "Circuit breaker state of the Milvus client: 0 closed, 1 open, 2 half-open.",
+		}, []string{"client"}),
+	}
+
+	var err error
+	if m.duration, err = registerCollector(reg, m.duration); err != nil {
+		return nil, err
+	}
+	if m.errors, err = registerCollector(reg, m.errors); err != nil {
+		return nil, err
+	}
+	if m.inFlight, err = registerCollector(reg, m.inFlight); err != nil {
+		return nil, err
+	}
+	if m.insertedRows, err = registerCollector(reg, m.insertedRows); err != nil {
+		return nil, err
+	}
+	if m.returnedRows, err = registerCollector(reg, m.returnedRows); err != nil {
+		return nil, err
+	}
+	if m.throttleWait, err = registerCollector(reg, m.throttleWait); err != nil {
+		return nil, err
+	}
+	if m.throttleWaiting, err = registerCollector(reg, m.throttleWaiting); err != nil {
+		return nil, err
+	}
+	if m.circuitState, err = registerCollector(reg, m.circuitState); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// registerCollector 注册指标，已存在相同指标时返回已注册的实例
+func registerCollector[T prometheus.Collector](reg prometheus.Registerer, c T) (T, error) {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing, nil
+			}
+		}
+		return c, errors.Wrap(err, "failed to register milvus client metrics")
+	}
+	return c, nil
+}
+
+// interceptor 返回记录指标的拦截器
+// name: client标签的值
+func (m *clientMetrics) interceptor(name string) Interceptor {
+	return func(ctx context.Context, info *OperationInfo, invoker Invoker) error {
+		inFlight := m.inFlight.WithLabelValues(name, info.Name)
+		inFlight.Inc()
+		start := time.Now()
+
+		err := invoker(ctx, info)
+
+		inFlight.Dec()
+		m.duration.WithLabelValues(name, info.Name).Observe(time.Since(start).Seconds())
+		if err != nil {
+			m.errors.WithLabelValues(name, info.Name, errorCode(err), errorKind(err)).Inc()
+		}
+		if info.ResultRows > 0 {
+			switch info.Name {
+			case "Insert":
+				m.insertedRows.WithLabelValues(name, info.Collection).Add(float64(info.ResultRows))
+			case "Search", "Query", "QueryWithLimit":
+				m.returnedRows.WithLabelValues(name, info.Collection, info.Name).Add(float64(info.ResultRows))
+			}
+		}
+		return err
+	}
+}
+
+// breakerStateChange 返回记录熔断器状态的回调，并将初始状态记录为关闭
+func (m *clientMetrics) breakerStateChange(name string) func(from, to BreakerState) {
+	state := m.circuitState.WithLabelValues(name)
+	state.Set(float64(BreakerClosed))
+	return func(from, to BreakerState) {
+		state.Set(float64(to))
+	}
+}
+
+// errorCode 返回错误码标签：Milvus错误码，gRPC错误返回状态码名称，其他错误返回"0"
+func errorCode(err error) string {
+	var e *Error
+	if errors.As(err, &e) && e.Code != 0 {
+		return strconv.Itoa(int(e.Code))
+	}
+	if s, ok := status.FromError(err); ok && s.Err() != nil {
+		return s.Code().String()
+	}
+	return "0"
+}
+
+// errorKind 返回错误分类标签，无法分类时返回"unknown"
+func errorKind(err error) string {
+	for _, k := range errorKindNames {
+		if errors.Is(err, k.kind) {
+			return k.name
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestMetrics 测试客户端指标
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("记录延迟与行数", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		c := newRetryTestClient(WithMetrics(reg, "main"))
+
+		insert := &OperationInfo{Name: "Insert", Class: OpWrite, Collection: "c1"}
+		require.NoError(t, c.invoke(ctx, insert, func(ctx context.Context) error {
+			insert.ResultRows = 5
+			return nil
+		}))
+		search := &OperationInfo{Name: "Search", Class: OpRead, Collection: "c1"}
+		require.NoError(t, c.invoke(ctx, search, func(ctx context.Context) error {
+			search.ResultRows = 20
+			return nil
+		}))
+
+		m, err := newClientMetrics(reg, nil)
+		require.NoError(t, err, "重复注册时复用已有指标")
+		assert.Equal(t, 5.0, testutil.ToFloat64(m.insertedRows.WithLabelValues("main", "c1")))
+		assert.Equal(t, 20.0, testutil.ToFloat64(m.returnedRows.WithLabelValues("main", "c1", "Search")))
+		assert.Equal(t, 0.0, testutil.ToFloat64(m.inFlight.WithLabelValues("main", "Insert")))
+		assert.Equal(t, 2, testutil.CollectAndCount(m.duration))
+		assert.Equal(t, 0, testutil.CollectAndCount(m.errors))
+	})
+
+	t.Run("按错误码记录错误", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		c := newRetryTestClient(WithMetrics(reg, ""), WithAddress("milvus:19530"), WithRetryPolicy(OpRead, nil))
+
+		_ = c.invoke(ctx, &OperationInfo{Name: "DescribeCollection", Class: OpRead, Collection: "c1"}, func(ctx context.Context) error {
+			return merr.Error(&commonpb.Status{Code: 100, Reason: "collection not found"})
+		})
+		_ = c.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead, Collection: "c1"}, func(ctx context.Context) error {
+			return status.Error(codes.Unavailable, "down")
+		})
+
+		m, err := newClientMetrics(reg, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, testutil.ToFloat64(m.errors.WithLabelValues("milvus:19530", "DescribeCollection", "100", "collection_not_found")))
+		assert.Equal(t, 1.0, testutil.ToFloat64(m.errors.WithLabelValues("milvus:19530", "Query", "Unavailable", "unavailable")))
+	})
+
+	t.Run("在途操作数", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		c := newRetryTestClient(WithMetrics(reg, "main"))
+		m, err := newClientMetrics(reg, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, c.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			assert.Equal(t, 1.0, testutil.ToFloat64(m.inFlight.WithLabelValues("main", "Query")))
+			return nil
+		}))
+		assert.Equal(t, 0.0, testutil.ToFloat64(m.inFlight.WithLabelValues("main", "Query")))
+	})
+
+	t.Run("常量标签区分同名客户端", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		a := newRetryTestClient(WithMetrics(reg, "main"), WithMetricsLabels(prometheus.Labels{"pool": "a"}))
+		b := newRetryTestClient(WithMetrics(reg, "main"), WithMetricsLabels(prometheus.Labels{"pool": "b"}))
+		unavailable := func(ctx context.Context) error { return status.Error(codes.Unavailable, "down") }
+		_ = a.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead}, unavailable)
+		_ = b.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead}, unavailable)
+		_ = b.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead}, unavailable)
+
+		ma, err := newClientMetrics(reg, prometheus.Labels{"pool": "a"})
+		require.NoError(t, err)
+		mb, err := newClientMetrics(reg, prometheus.Labels{"pool": "b"})
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, testutil.ToFloat64(ma.errors.WithLabelValues("main", "Query", "Unavailable", "unavailable")))
+		assert.Equal(t, 2.0, testutil.ToFloat64(mb.errors.WithLabelValues("main", "Query", "Unavailable", "unavailable")))
+	})
+
+	t.Run("指标冲突", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		reg.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: "milvus_client_inserted_rows_total", Help: "other"}))
+		_, err := buildInterceptor(&Options{MetricsRegisterer: reg})
+		assert.Error(t, err)
+	})
+}
@@ -1,8 +1,16 @@
 package client
 
 import (
+	"crypto/tls"
+	"log/slog"
 	"math"
 	"time"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 )
 
 // Options 定义Milvus客户端的配置选项
@@ -13,12 +21,33 @@ type Options struct {
 	Password string // 密码，用于身份验证
 	DBName   string // 数据库名称，指定要连接的数据库，默认为default
 
-	EnableTLSAuth bool   // 是否启用TLS安全传输，如果地址使用https://则自动启用
-	APIKey        string // API密钥认证，与用户名/密码认证互斥，优先使用APIKey
+	// 多地址故障转移配置
+	Endpoints []Endpoint      // 按优先级故障转移的服务地址，非空时忽略Address
+	Failover  *FailoverPolicy // 故障转移策略，nil表示使用DefaultFailoverPolicy
+
+	EnableTLSAuth bool       // 是否启用TLS安全传输，如果地址使用https://则自动启用
+	APIKey        string     // API密钥认证，与用户名/密码认证互斥，优先使用APIKey
+	TLS           *TLSConfig // TLS与mTLS配置，非nil时启用TLS，nil表示使用系统根证书且不提供客户端证书
+
+	CredentialProvider CredentialProvider // 凭据提供者，非nil时每次请求从中获取凭据，忽略Username、Password与APIKey
 
 	// 重试配置
-	MaxRetry        uint          // 最大重试次数
-	MaxRetryBackoff time.Duration // 最大重试退避时间
+	MaxRetry        uint          // SDK遇到服务端限流时的最大重试次数
+	MaxRetryBackoff time.Duration // SDK遇到服务端限流时的最大重试退避时间
+
+	// 客户端重试配置，在SDK限流重试之外，按操作类别重试可重试的错误
+	RetryPolicies          map[OperationClass]*RetryPolicy // 各操作类别的重试策略，未配置的类别不重试
+	RetryBudget            *RetryBudget                    // 重试预算，nil表示不限制
+	AllowAutoIDInsertRetry bool                            // 是否允许重试自动生成主键的Insert，重试可能写入重复数据
+
+	// 限流配置
+	RateLimits map[OperationClass]*RateLimit // 各操作类别的客户端限流与并发上限，未配置的类别不限制
+
+	// 熔断配置
+	CircuitBreaker *CircuitBreaker // 熔断器配置，nil表示不启用
+
+	// 超时配置
+	Timeouts map[OperationClass]time.Duration // 各操作类别的默认超时时间，仅在ctx没有截止时间时生效，未配置的类别不设置超时
 
 	// GRPC连接配置
 	// 注意：这些配置项主要用于高级用户，大多数情况下使用默认值即可
@@ -33,8 +62,32 @@ type Options struct {
 	MinConnectTimeout   time.Duration // 最小连接超时时间，连接建立的最短超时
 	MaxRecvMsgSize      int           // 最大接收消息大小，0表示使用默认值(2GB-1)
 
+	// 请求拆分配置
+	MaxRequestSize int // 单次请求的最大估算字节数，超过时Insert按行、Search按查询向量自动拆分，0表示不拆分
+	MaxSearchNQ    int // 单次Search请求的最大查询向量数，超过时自动拆分，0表示不限制
+
+	// 拦截器配置
+	Interceptors       []Interceptor                  // 客户端操作拦截器，按顺序从外到内包裹每一次Client方法调用
+	UnaryInterceptors  []grpc.UnaryClientInterceptor  // 追加到gRPC连接的一元调用拦截器
+	StreamInterceptors []grpc.StreamClientInterceptor // 追加到gRPC连接的流式调用拦截器
+
+	// 追踪配置
+	TracerProvider trace.TracerProvider          // OpenTelemetry TracerProvider，非nil时为每个操作创建span
+	Propagator     propagation.TextMapPropagator // 通过gRPC元数据传播追踪上下文的传播器，nil表示使用全局传播器
+
+	// 指标配置
+	MetricsRegisterer prometheus.Registerer // Prometheus指标注册器，非nil时记录操作指标
+	MetricsName       string                // 指标client标签的值，为空时使用服务地址
+	MetricsLabels     prometheus.Labels     // 附加到所有客户端指标的常量标签，例如连接池设置的pool标签
+
+	// 日志配置
+	Logger        *slog.Logger  // 日志记录器，记录连接、重连、关闭、重试与慢操作，nil表示不记录日志
+	SlowThreshold time.Duration // 慢操作阈值，耗时达到该值的操作记录警告日志，0表示不记录
+
 	// 其他配置
 	DisableConn bool // 是否禁用连接握手，true时跳过向Milvus服务器发送ConnectRequest，通常用于测试或特殊场景
+
+	err error // 应用选项时的错误，例如WithURI解析失败，由NewWithOptions返回
 }
 
 // DefaultOptions 返回默认配置
@@ -46,6 +99,9 @@ func DefaultOptions() *Options {
 		MaxRetry:        75,              // 默认最大重试75次
 		MaxRetryBackoff: 3 * time.Second, // 默认最大退避3秒
 
+		RetryPolicies: DefaultRetryPolicies(),
+		Timeouts:      DefaultTimeouts(),
+
 		// GRPC 默认配置 - 与 milvusclient.DefaultGrpcOpts 保持一致
 		WithBlock:           true,
 		KeepaliveTime:       5 * time.Second,
@@ -58,6 +114,11 @@ func DefaultOptions() *Options {
 		MinConnectTimeout:   3 * time.Second,
 		MaxRecvMsgSize:      math.MaxInt32, // 2GB - 1
 
+		SlowThreshold: time.Second, // 默认耗时1秒以上为慢操作
+
+		MaxRequestSize: 64 << 20, // 64MB，与Milvus proxy默认的gRPC最大接收消息大小保持一致
+		MaxSearchNQ:    16384,    // 与Milvus默认的nq上限保持一致
+
 		DisableConn: false,
 	}
 }
@@ -72,6 +133,22 @@ func WithAddress(address string) Option {
 	}
 }
 
+// WithEndpoints 设置多个服务地址，在地址之间按优先级故障转移，设置后忽略WithAddress
+// endpoints: 服务地址与优先级，优先级数值越小越优先
+func WithEndpoints(endpoints ...Endpoint) Option {
+	return func(o *Options) {
+		o.Endpoints = endpoints
+	}
+}
+
+// WithFailover 设置故障转移策略，仅在设置WithEndpoints时生效
+// policy: 故障转移策略，nil表示使用DefaultFailoverPolicy
+func WithFailover(policy *FailoverPolicy) Option {
+	return func(o *Options) {
+		o.Failover = policy
+	}
+}
+
 // WithAuth 设置用户名密码认证
 func WithAuth(username, password string) Option {
 	return func(o *Options) {
@@ -90,6 +167,15 @@ func WithAPIKey(apiKey string) Option {
 	}
 }
 
+// WithCredentialProvider 设置凭据提供者，每次请求从中获取凭据，凭据轮换无需重建客户端
+// 设置后忽略WithAuth与WithAPIKey设置的静态凭据
+// provider: 凭据提供者，例如NewFileCredentials、NewRefreshingCredentials，nil表示使用静态凭据
+func WithCredentialProvider(provider CredentialProvider) Option {
+	return func(o *Options) {
+		o.CredentialProvider = provider
+	}
+}
+
 // WithDatabase 设置数据库
 func WithDatabase(dbName string) Option {
 	return func(o *Options) {
@@ -104,7 +190,62 @@ func WithTLS() Option {
 	}
 }
 
-// WithRetry 设置重试配置
+// tlsConfig 返回o.TLS的副本用于修改并启用TLS，避免影响共享同一配置的其他Options
+func tlsConfig(o *Options) *TLSConfig {
+	cfg := &TLSConfig{}
+	if o.TLS != nil {
+		*cfg = *o.TLS
+	}
+	o.TLS = cfg
+	o.EnableTLSAuth = true
+	return cfg
+}
+
+// WithTLSConfig 使用自定义的tls.Config启用TLS，WithTLSFiles等选项覆盖其中的对应配置
+// cfg: 基础TLS配置，创建客户端时会复制，之后修改不影响客户端
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *Options) {
+		tlsConfig(o).Config = cfg
+	}
+}
+
+// WithTLSFiles 使用证书文件启用TLS，提供客户端证书与私钥时启用mTLS
+// caFile: 校验服务端证书的CA证书文件，为空时使用系统根证书
+// certFile: 客户端证书文件，为空时不提供客户端证书
+// keyFile: 客户端私钥文件，与certFile同时设置
+func WithTLSFiles(caFile, certFile, keyFile string) Option {
+	return func(o *Options) {
+		cfg := tlsConfig(o)
+		cfg.CAFile = caFile
+		cfg.CertFile = certFile
+		cfg.KeyFile = keyFile
+	}
+}
+
+// WithTLSServerName 设置校验服务端证书使用的名称，用于通过IP或负载均衡地址连接时
+func WithTLSServerName(serverName string) Option {
+	return func(o *Options) {
+		tlsConfig(o).ServerName = serverName
+	}
+}
+
+// WithTLSMinVersion 设置最低TLS版本
+// version: TLS版本，例如tls.VersionTLS13
+func WithTLSMinVersion(version uint16) Option {
+	return func(o *Options) {
+		tlsConfig(o).MinVersion = formatTLSVersion(version)
+	}
+}
+
+// WithTLSReload 启用证书文件的热加载，证书文件变化后新建立的连接使用新证书
+// interval: 检查证书文件变化的最小间隔，0表示不重新加载
+func WithTLSReload(interval time.Duration) Option {
+	return func(o *Options) {
+		tlsConfig(o).ReloadInterval = interval
+	}
+}
+
+// WithRetry 设置SDK遇到服务端限流时的重试配置，客户端按操作类别的重试见WithRetryPolicy
 func WithRetry(maxRetry uint, maxBackoff time.Duration) Option {
 	return func(o *Options) {
 		o.MaxRetry = maxRetry
@@ -112,6 +253,147 @@ func WithRetry(maxRetry uint, maxBackoff time.Duration) Option {
 	}
 }
 
+// WithRetryPolicy 设置某一操作类别的重试策略
+// class: 操作类别，例如client.OpRead
+// policy: 重试策略，nil表示该类别不重试
+func WithRetryPolicy(class OperationClass, policy *RetryPolicy) Option {
+	return func(o *Options) {
+		if o.RetryPolicies == nil {
+			o.RetryPolicies = make(map[OperationClass]*RetryPolicy)
+		}
+		o.RetryPolicies[class] = policy
+	}
+}
+
+// WithTimeout 设置某一操作类别的默认超时时间，仅在调用方的ctx没有截止时间时生效
+// 单次调用可通过ContextWithTimeout覆盖
+// class: 操作类别，例如client.OpRead
+// timeout: 超时时间，0表示该类别不设置默认超时
+func WithTimeout(class OperationClass, timeout time.Duration) Option {
+	return func(o *Options) {
+		if o.Timeouts == nil {
+			o.Timeouts = make(map[OperationClass]time.Duration)
+		}
+		o.Timeouts[class] = timeout
+	}
+}
+
+// WithRateLimit 设置某一操作类别的客户端限流与并发上限
+// 等待限流额度时受ctx控制，超过截止时间返回client.ErrRateLimited
+// class: 操作类别，例如client.OpWrite
+// limit: 限流配置，nil表示该类别不限制
+func WithRateLimit(class OperationClass, limit *RateLimit) Option {
+	return func(o *Options) {
+		if o.RateLimits == nil {
+			o.RateLimits = make(map[OperationClass]*RateLimit)
+		}
+		o.RateLimits[class] = limit
+	}
+}
+
+// WithCircuitBreaker 启用熔断器
+// 错误率或慢请求比例超过阈值时熔断器打开，此后的操作直接返回client.ErrCircuitOpen，不再发送到服务端
+// cb: 熔断器配置，例如client.DefaultCircuitBreaker()，nil表示不启用
+func WithCircuitBreaker(cb *CircuitBreaker) Option {
+	return func(o *Options) {
+		o.CircuitBreaker = cb
+	}
+}
+
+// WithRetryBudget 设置重试预算
+// ratio: 每次调用增加的重试额度，例如0.1表示重试次数约为调用次数的10%
+// burst: 最多累积的重试额度，例如10
+func WithRetryBudget(ratio, burst float64) Option {
+	return func(o *Options) {
+		o.RetryBudget = &RetryBudget{Ratio: ratio, Burst: burst}
+	}
+}
+
+// WithAutoIDInsertRetry 设置是否允许重试自动生成主键的Insert
+// 超时等错误发生时数据可能已经写入，重试会以新的主键再次写入，只有能容忍重复数据时才应开启
+func WithAutoIDInsertRetry(allow bool) Option {
+	return func(o *Options) {
+		o.AllowAutoIDInsertRetry = allow
+	}
+}
+
+// WithInterceptors 添加客户端操作拦截器，可多次调用，先添加的拦截器位于外层
+// 拦截器可获取操作名称、集合、参数摘要与结果行数，并记录耗时与错误，例如：
+//
+//	client.WithInterceptors(func(ctx context.Context, info *client.OperationInfo, invoker client.Invoker) error {
+//		start := time.Now()
+//		err := invoker(ctx, info)
+//		log.Printf("%s %s %v %v", info.Name, info.Collection, time.Since(start), err)
+//		return err
+//	})
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(o *Options) {
+		o.Interceptors = append(o.Interceptors, interceptors...)
+	}
+}
+
+// WithUnaryInterceptors 添加gRPC一元调用拦截器，可多次调用
+func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) Option {
+	return func(o *Options) {
+		o.UnaryInterceptors = append(o.UnaryInterceptors, interceptors...)
+	}
+}
+
+// WithStreamInterceptors 添加gRPC流式调用拦截器，可多次调用
+func WithStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) Option {
+	return func(o *Options) {
+		o.StreamInterceptors = append(o.StreamInterceptors, interceptors...)
+	}
+}
+
+// WithTracing 启用OpenTelemetry追踪
+// 每个操作创建一个span，并通过gRPC元数据将追踪上下文传播到服务端
+// tp: TracerProvider，nil表示关闭追踪
+// propagator: 追踪上下文传播器，nil表示使用otel.GetTextMapPropagator()
+func WithTracing(tp trace.TracerProvider, propagator propagation.TextMapPropagator) Option {
+	return func(o *Options) {
+		o.TracerProvider = tp
+		o.Propagator = propagator
+	}
+}
+
+// WithMetrics 启用Prometheus指标，将操作延迟、错误、并发数与行数指标注册到reg
+// 多个客户端可共享同一个reg，通过client标签区分
+// reg: 指标注册器，例如prometheus.DefaultRegisterer，nil表示关闭指标
+// name: client标签的值，为空时使用服务地址
+func WithMetrics(reg prometheus.Registerer, name string) Option {
+	return func(o *Options) {
+		o.MetricsRegisterer = reg
+		o.MetricsName = name
+	}
+}
+
+// WithMetricsLabels 为客户端指标附加常量标签，常量标签不同的客户端在同一个注册器上使用不同的指标序列
+// 同一注册器上的客户端需要使用相同的标签名，例如连接池中的客户端都带有pool标签
+// labels: 常量标签，例如prometheus.Labels{"pool": "analytics"}
+func WithMetricsLabels(labels prometheus.Labels) Option {
+	return func(o *Options) {
+		o.MetricsLabels = labels
+	}
+}
+
+// WithLogger 设置日志记录器
+// 记录连接建立、断开与重连、客户端关闭、操作重试与慢操作，日志中不包含密码、APIKey与向量数据
+// logger: 日志记录器，nil表示不记录日志
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *Options) {
+		o.Logger = logger
+	}
+}
+
+// WithSlowThreshold 设置慢操作阈值
+// threshold: 耗时达到该值的操作记录警告日志，0表示不记录慢操作
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(o *Options) {
+		o.SlowThreshold = threshold
+	}
+}
+
 // WithGrpcOpts 集中设置所有GRPC连接配置
 // 注意：这些配置项主要用于高级用户，大多数情况下使用默认值即可
 // keepaliveTime: Keepalive时间间隔，用于保持连接活跃
@@ -153,3 +435,58 @@ func WithDisableConn(disable bool) Option {
 		o.DisableConn = disable
 	}
 }
+
+// WithRequestLimits 设置请求拆分阈值
+// maxRequestSize: 单次请求的最大估算字节数，超过时Insert、Search自动拆分为多次请求，0表示不拆分
+// maxSearchNQ: 单次Search请求的最大查询向量数，0表示不限制
+// 注意：拆分后的Insert不是原子操作，部分批次失败时已写入的数据不会回滚
+func WithRequestLimits(maxRequestSize, maxSearchNQ int) Option {
+	return func(o *Options) {
+		o.MaxRequestSize = maxRequestSize
+		o.MaxSearchNQ = maxSearchNQ
+	}
+}
+
+// CollectionOptions 创建集合的配置选项
+type CollectionOptions struct {
+	ShardNum         int32                   // 分片数量
+	ConsistencyLevel entity.ConsistencyLevel // 一致性级别
+	Properties       map[string]string       // 集合属性，例如"collection.ttl.seconds"
+}
+
+// DefaultCollectionOptions 返回创建集合的默认配置，与SDK的默认值一致
+func DefaultCollectionOptions() *CollectionOptions {
+	return &CollectionOptions{
+		ShardNum:         1,                              // 默认1个分片
+		ConsistencyLevel: entity.DefaultConsistencyLevel, // 默认Bounded一致性
+		Properties:       make(map[string]string),
+	}
+}
+
+// CollectionOption 定义创建集合的配置选项函数类型
+type CollectionOption func(*CollectionOptions)
+
+// WithShardNum 设置集合的分片数量
+// shardNum: 分片数量，建议值为1-8
+func WithShardNum(shardNum int32) CollectionOption {
+	return func(o *CollectionOptions) {
+		o.ShardNum = shardNum
+	}
+}
+
+// WithConsistencyLevel 设置集合的一致性级别
+// level: 一致性级别，例如entity.ClStrong、entity.ClBounded
+func WithConsistencyLevel(level entity.ConsistencyLevel) CollectionOption {
+	return func(o *CollectionOptions) {
+		o.ConsistencyLevel = level
+	}
+}
+
+// WithCollectionProperty 设置集合属性
+// key: 属性名，例如"collection.ttl.seconds"
+// value: 属性值，例如"86400"
+func WithCollectionProperty(key, value string) CollectionOption {
+	return func(o *CollectionOptions) {
+		o.Properties[key] = value
+	}
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// RateLimit 某一操作类别的客户端限流配置，值为0的字段表示不限制
+// 限流在客户端内生效，多个客户端之间不共享额度
+type RateLimit struct {
+	RequestsPerSecond float64 // 每秒请求数，Insert与Search被拆分时每个分段计为一次请求
+	RequestBurst      int     // 请求突发量，0表示取RequestsPerSecond向上取整
+	RowsPerSecond     float64 // 每秒写入行数，按Insert请求的行数计算
+	RowBurst          int     // 行数突发量，0表示取RowsPerSecond向上取整
+	MaxInFlight       int     // 同时执行的最大操作数，超过时等待
+}
+
+// classLimiter 某一操作类别的限流器
+type classLimiter struct {
+	class    OperationClass
+	requests *rate.Limiter
+	rows     *rate.Limiter
+	slots    chan struct{}
+}
+
+// newClassLimiter 按配置创建限流器
+func newClassLimiter(class OperationClass, limit *RateLimit) *classLimiter {
+	l := &classLimiter{class: class}
+	if limit.RequestsPerSecond > 0 {
+		l.requests = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst(limit.RequestBurst, limit.RequestsPerSecond))
+	}
+	if limit.RowsPerSecond > 0 {
+		l.rows = rate.NewLimiter(rate.Limit(limit.RowsPerSecond), burst(limit.RowBurst, limit.RowsPerSecond))
+	}
+	if limit.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// burst 返回令牌桶容量，未配置时取速率向上取整
+func burst(configured int, perSecond float64) int {
+	if configured > 0 {
+		return configured
+	}
+	return int(math.Max(1, math.Ceil(perSecond)))
+}
+
+// acquire 等待请求、行数额度与执行槽位，返回释放槽位的函数
+func (l *classLimiter) acquire(ctx context.Context, rows int) (func(), error) {
+	if l.requests != nil {
+		if err := l.requests.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if l.rows != nil {
+		// 行数超过突发量时分批获取，避免WaitN直接返回错误
+		for rows > 0 {
+			n := min(rows, l.rows.Burst())
+			if err := l.rows.WaitN(ctx, n); err != nil {
+				return nil, err
+			}
+			rows -= n
+		}
+	}
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// rateLimitInterceptor 按操作类别限流，等待额度超过ctx截止时间时返回ErrRateLimited
+// metrics不为nil时记录等待时间与等待中的操作数
+func rateLimitInterceptor(limits map[OperationClass]*RateLimit, metrics *clientMetrics, name string) Interceptor {
+	limiters := make(map[OperationClass]*classLimiter, len(limits))
+	for class, limit := range limits {
+		if limit != nil {
+			limiters[class] = newClassLimiter(class, limit)
+		}
+	}
+	return func(ctx context.Context, info *OperationInfo, invoker Invoker) error {
+		l, ok := limiters[info.Class]
+		if !ok {
+			return invoker(ctx, info)
+		}
+
+		rows, _ := info.Args["rows"].(int)
+		start := time.Now()
+		if metrics != nil {
+			metrics.throttleWaiting.WithLabelValues(name, info.Class.String()).Inc()
+		}
+		release, err := l.acquire(ctx, rows)
+		if metrics != nil {
+			metrics.throttleWaiting.WithLabelValues(name, info.Class.String()).Dec()
+			metrics.throttleWait.WithLabelValues(name, info.Class.String()).Observe(time.Since(start).Seconds())
+		}
+		if err != nil {
+			return &Error{Op: info.Name, Kind: ErrRateLimited, Err: errors.Wrap(err, "client-side rate limit")}
+		}
+		defer release()
+		return invoker(ctx, info)
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// TestRateLimit 测试客户端限流与并发上限
+func TestRateLimit(t *testing.T) {
+	ctx := context.Background()
+	noop := func(ctx context.Context) error { return nil }
+
+	t.Run("每秒请求数", func(t *testing.T) {
+		c := newRetryTestClient(WithRateLimit(OpRead, &RateLimit{RequestsPerSecond: 20, RequestBurst: 1}))
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			require.NoError(t, c.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead}, noop))
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+	})
+
+	t.Run("每秒写入行数", func(t *testing.T) {
+		c := newRetryTestClient(WithRateLimit(OpWrite, &RateLimit{RowsPerSecond: 1000, RowBurst: 50}))
+		start := time.Now()
+		op := &OperationInfo{Name: "Insert", Class: OpWrite, Args: map[string]any{"rows": 150}}
+		require.NoError(t, c.invoke(ctx, op, noop))
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond, "超过突发量的行数分批等待")
+	})
+
+	t.Run("并发上限", func(t *testing.T) {
+		c := newRetryTestClient(WithRateLimit(OpWrite, &RateLimit{MaxInFlight: 2}))
+		var current, peak atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = c.invoke(ctx, &OperationInfo{Name: "Insert", Class: OpWrite}, func(ctx context.Context) error {
+					n := current.Add(1)
+					for {
+						p := peak.Load()
+						if n <= p || peak.CompareAndSwap(p, n) {
+							break
+						}
+					}
+					time.Sleep(20 * time.Millisecond)
+					current.Add(-1)
+					return nil
+				})
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(2), peak.Load())
+	})
+
+	t.Run("等待超过截止时间", func(t *testing.T) {
+		c := newRetryTestClient(WithRateLimit(OpRead, &RateLimit{RequestsPerSecond: 1}))
+		require.NoError(t, c.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead}, noop))
+
+		called := false
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		err := c.invoke(timeoutCtx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.False(t, IsRetryable(err))
+		assert.False(t, called)
+	})
+
+	t.Run("限流等待中的写入不阻塞读取", func(t *testing.T) {
+		cli, err := NewWithOptions(ctx, WithAddress(testserver.Start(t)),
+			WithRateLimit(OpWrite, &RateLimit{RowsPerSecond: 1, RowBurst: 1}))
+		require.NoError(t, err)
+
+		// 10行数据需要等待约9秒的行数额度
+		insertCtx, cancel := context.WithCancel(ctx)
+		inserted := make(chan error, 1)
+		go func() {
+			_, err := cli.Insert(insertCtx, "docs", "", column.NewColumnInt64("id", make([]int64, 10)))
+			inserted <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		searchCtx, searchCancel := context.WithTimeout(ctx, 2*time.Second)
+		defer searchCancel()
+		_, err = cli.Search(searchCtx, "docs", nil, nil, []entity.Vector{entity.FloatVector{0.1}}, "vector", entity.L2, 1, "", nil)
+		assert.ErrorIs(t, err, ErrUnsupported, "搜索到达服务端，没有等待写入的限流")
+		select {
+		case err := <-inserted:
+			require.FailNow(t, "insert should still be throttled", "%v", err)
+		default:
+		}
+
+		cancel()
+		assert.ErrorIs(t, <-inserted, ErrRateLimited)
+		require.NoError(t, cli.Close())
+	})
+
+	t.Run("未配置的类别不限制", func(t *testing.T) {
+		c := newRetryTestClient(WithRateLimit(OpRead, &RateLimit{RequestsPerSecond: 1}))
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			require.NoError(t, c.invoke(ctx, &OperationInfo{Name: "CreateIndex", Class: OpDDL}, noop))
+		}
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("记录等待指标", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		c := newRetryTestClient(WithMetrics(reg, "main"), WithRateLimit(OpRead, &RateLimit{MaxInFlight: 1}))
+		require.NoError(t, c.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead}, noop))
+		require.NoError(t, c.invoke(ctx, &OperationInfo{Name: "Search", Class: OpRead}, noop))
+
+		m, err := newClientMetrics(reg, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, testutil.CollectAndCount(m.throttleWait))
+		assert.Equal(t, 0.0, testutil.ToFloat64(m.throttleWaiting.WithLabelValues("main", "read")))
+	})
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OperationClass 操作类别，不同类别使用不同的重试策略
+type OperationClass int
+
+const (
+	OpRead  OperationClass = iota // 读操作：Search、Query、Has*、Describe*、List*等
+	OpWrite                       // 数据写入：Insert、Delete
+	OpDDL                         // 元数据变更：创建/删除集合、分区、索引、别名、数据库，加载/释放，压缩
+)
+
+// String 返回操作类别名称
+func (c OperationClass) String() string {
+	switch c {
+	case OpRead:
+		return "read"
+	case OpWrite:
+		return "write"
+	case OpDDL:
+		return "ddl"
+	default:
+		return "unknown"
+	}
+}
+
+// RetryPolicy 重试策略
+// 第n次重试前等待 min(InitialBackoff * Multiplier^(n-1), MaxBackoff)，并在 ±Jitter 比例内随机抖动
+type RetryPolicy struct {
+	MaxAttempts    int                  // 最大尝试次数（含首次调用），小于等于1表示不重试
+	InitialBackoff time.Duration        // 首次重试前的等待时间
+	MaxBackoff     time.Duration        // 最大等待时间
+	Multiplier     float64              // 等待时间的增长倍数
+	Jitter         float64              // 抖动比例，0-1之间，避免大量客户端同时重试
+	Retryable      func(err error) bool // 判断错误是否可重试，nil表示使用IsRetryable并跳过SDK已重试的gRPC错误
+}
+
+// DefaultRetryPolicies 返回默认的重试策略
+// 读操作与数据写入最多尝试3次；元数据变更默认不重试，因为超时后请求可能已在服务端生效
+func DefaultRetryPolicies() map[OperationClass]*RetryPolicy {
+	return map[OperationClass]*RetryPolicy{
+		OpRead: {
+			MaxAttempts:    3,
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     2 * time.Second,
+			Multiplier:     2,
+			Jitter:         0.2,
+		},
+		OpWrite: {
+			MaxAttempts:    3,
+			InitialBackoff: 200 * time.Millisecond,
+			MaxBackoff:     3 * time.Second,
+			Multiplier:     2,
+			Jitter:         0.2,
+		},
+	}
+}
+
+// retryable 判断错误是否可按该策略重试
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err) && !retriedBySDK(err)
+}
+
+// retriedBySDK 判断错误是否已由SDK内置的gRPC重试拦截器重试过
+// SDK对Unavailable与ResourceExhausted最多重试6次，再次重试会使尝试次数成倍增加
+func retriedBySDK(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff 返回第attempt次重试前的等待时间，attempt从1开始
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// RetryBudget 重试预算，限制重试请求占全部请求的比例，避免服务端故障时重试放大流量
+// 每次调用为预算增加Ratio个额度（最多累积到Burst），每次重试消耗1个额度，额度不足时不再重试
+type RetryBudget struct {
+	Ratio float64 // 每次调用增加的重试额度，例如0.1表示重试次数约为调用次数的10%
+	Burst float64 // 最多累积的重试额度，同时也是初始额度
+}
+
+// retryBudget 重试预算的运行状态
+type retryBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	burst  float64
+	tokens float64
+}
+
+func newRetryBudget(b *RetryBudget) *retryBudget {
+	if b == nil {
+		return nil
+	}
+	return &retryBudget{ratio: b.Ratio, burst: b.Burst, tokens: b.Burst}
+}
+
+// deposit 记录一次调用
+func (b *retryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.tokens+b.ratio, b.burst)
+}
+
+// withdraw 尝试消耗一次重试额度
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// noRetryKey 禁止客户端重试的上下文键，由自行重试的调用方（如BatchWriter）设置
+type noRetryKey struct{}
+
+// withoutRetry 返回禁止客户端按重试策略重试的上下文
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// retry 按操作类别的重试策略执行操作，并转换错误
+func (c *client) retry(ctx context.Context, op *OperationInfo, fn func(ctx context.Context) error) error {
+	policy := c.options.RetryPolicies[op.Class]
+	if ctx.Value(noRetryKey{}) != nil {
+		policy = nil
+	}
+	if c.budget != nil {
+		c.budget.deposit()
+	}
+
+	for attempt := 1; ; attempt++ {
+		op.Attempts = attempt
+		err := convertError(op.Name, fn(ctx))
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !policy.retryable(err) {
+			return err
+		}
+		if op.mayRetry != nil && !op.mayRetry(ctx) {
+			return err
+		}
+		if c.budget != nil && !c.budget.withdraw() {
+			return err
+		}
+
+		backoff := policy.backoff(attempt)
+		c.logger.WarnContext(ctx, "retrying milvus operation",
+			slog.String("op", op.Name),
+			slog.String("collection", op.Collection),
+			slog.Int("attempt", attempt),
+			slog.Duration("backoff", backoff),
+			slog.Any("error", err),
+		)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// isAutoID 判断集合是否自动生成主键，结果按集合缓存；无法获取集合信息时按自动生成处理
+func (c *client) isAutoID(ctx context.Context, collectionName string) bool {
+	if v, ok := c.autoID.Load(collectionName); ok {
+		return v.(bool)
+	}
+	coll, err := c.cli.DescribeCollection(ctx, milvusclient.NewDescribeCollectionOption(collectionName))
+	if err != nil || coll.Schema == nil {
+		return true
+	}
+	autoID := schemaAutoID(coll.Schema)
+	c.autoID.Store(collectionName, autoID)
+	return autoID
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newRetryTestClient 创建只用于测试invoke的客户端，不建立连接
+func newRetryTestClient(opts ...Option) *client {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	interceptor, err := buildInterceptor(options)
+	if err != nil {
+		panic(err)
+	}
+	return &client{
+		options:     options,
+		budget:      newRetryBudget(options.RetryBudget),
+		interceptor: interceptor,
+		logger:      newLogger(options),
+		database:    options.DBName,
+	}
+}
+
+// fastPolicy 退避时间很短的重试策略
+func fastPolicy(attempts int) *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: attempts, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 2}
+}
+
+// TestRetryPolicies 测试按操作类别重试
+func TestRetryPolicies(t *testing.T) {
+	ctx := context.Background()
+	unavailable := merr.WrapErrServiceNotReady("proxy", 1, "Initializing")
+
+	t.Run("可重试错误", func(t *testing.T) {
+		c := newRetryTestClient(WithRetryPolicy(OpRead, fastPolicy(3)))
+		calls := 0
+		err := c.retry(ctx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return unavailable
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("超过最大尝试次数", func(t *testing.T) {
+		c := newRetryTestClient(WithRetryPolicy(OpRead, fastPolicy(2)))
+		calls := 0
+		err := c.retry(ctx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			calls++
+			return unavailable
+		})
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("不可重试错误", func(t *testing.T) {
+		c := newRetryTestClient(WithRetryPolicy(OpRead, fastPolicy(3)))
+		calls := 0
+		err := c.retry(ctx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			calls++
+			return status.Error(codes.InvalidArgument, "bad expr")
+		})
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("不重试SDK已重试的gRPC错误", func(t *testing.T) {
+		c := newRetryTestClient(WithRetryPolicy(OpRead, fastPolicy(3)))
+		for _, code := range []codes.Code{codes.Unavailable, codes.ResourceExhausted} {
+			calls := 0
+			err := c.retry(ctx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+				calls++
+				return status.Error(code, "connection refused")
+			})
+			assert.True(t, IsRetryable(err))
+			assert.Equal(t, 1, calls, code.String())
+		}
+	})
+
+	t.Run("DDL默认不重试", func(t *testing.T) {
+		c := newRetryTestClient()
+		calls := 0
+		_ = c.retry(ctx, &OperationInfo{Name: "CreateCollection", Class: OpDDL}, func(ctx context.Context) error {
+			calls++
+			return unavailable
+		})
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("调用方自行重试时不重试", func(t *testing.T) {
+		c := newRetryTestClient(WithRetryPolicy(OpWrite, fastPolicy(3)))
+		calls := 0
+		_ = c.retry(withoutRetry(ctx), &OperationInfo{Name: "Insert", Class: OpWrite}, func(ctx context.Context) error {
+			calls++
+			return unavailable
+		})
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("操作禁止重试", func(t *testing.T) {
+		c := newRetryTestClient(WithRetryPolicy(OpWrite, fastPolicy(3)))
+		calls := 0
+		op := &OperationInfo{Name: "Insert", Class: OpWrite, mayRetry: func(ctx context.Context) bool { return false }}
+		_ = c.retry(ctx, op, func(ctx context.Context) error {
+			calls++
+			return unavailable
+		})
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("自定义可重试判断", func(t *testing.T) {
+		policy := fastPolicy(3)
+		policy.Retryable = func(err error) bool { return errors.Is(err, ErrInvalidParameter) }
+		c := newRetryTestClient(WithRetryPolicy(OpRead, policy))
+		calls := 0
+		_ = c.retry(ctx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			calls++
+			return status.Error(codes.InvalidArgument, "bad expr")
+		})
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("重试预算", func(t *testing.T) {
+		c := newRetryTestClient(WithRetryPolicy(OpRead, fastPolicy(10)), WithRetryBudget(0, 2))
+		calls := 0
+		_ = c.retry(ctx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			calls++
+			return unavailable
+		})
+		assert.Equal(t, 3, calls, "预算只允许2次重试")
+	})
+
+	t.Run("上下文取消", func(t *testing.T) {
+		policy := fastPolicy(3)
+		policy.InitialBackoff, policy.MaxBackoff = time.Hour, time.Hour
+		c := newRetryTestClient(WithRetryPolicy(OpRead, policy))
+		cancelCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		calls := 0
+		err := c.retry(cancelCtx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			calls++
+			return unavailable
+		})
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+// TestRetryPolicyBackoff 测试退避时间
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3))
+	assert.Equal(t, time.Second, p.backoff(10))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.backoff(1)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 150*time.Millisecond)
+	}
+}
+
+// TestRetryBudget 测试重试预算
+func TestRetryBudget(t *testing.T) {
+	assert.Nil(t, newRetryBudget(nil))
+
+	b := newRetryBudget(&RetryBudget{Ratio: 0.5, Burst: 1})
+	assert.True(t, b.withdraw())
+	assert.False(t, b.withdraw())
+
+	b.deposit()
+	b.deposit()
+	b.deposit() // 不超过Burst
+	assert.True(t, b.withdraw())
+	assert.False(t, b.withdraw())
+}
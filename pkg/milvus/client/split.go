@@ -0,0 +1,264 @@
+package client
+
+import (
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// splitColumns 按估算的请求大小将列数据拆分为多段，各段行数相近且顺序与原数据一致
+// maxSize <= 0 或数据未超过maxSize时不拆分
+func splitColumns(columns []column.Column, maxSize int) [][]column.Column {
+	if maxSize <= 0 || len(columns) == 0 {
+		return [][]column.Column{columns}
+	}
+
+	rows := columnRows(columns)
+	size := 0
+	for _, col := range columns {
+		size += columnSize(col)
+	}
+	if size <= maxSize || rows <= 1 {
+		return [][]column.Column{columns}
+	}
+
+	// 按平均行大小估算每段行数，单行超过上限时仍逐行发送，由服务端决定是否接受
+	perChunk := int(int64(rows) * int64(maxSize) / int64(size))
+	if perChunk < 1 {
+		perChunk = 1
+	}
+
+	dynamic := dynamicColumns(columns)
+	chunks := make([][]column.Column, 0, (rows+perChunk-1)/perChunk)
+	for start := 0; start < rows; start += perChunk {
+		chunks = append(chunks, sliceColumns(columns, dynamic, start, min(start+perChunk, rows)))
+	}
+	return chunks
+}
+
+// dynamicColumns 返回各列是否为动态字段列
+func dynamicColumns(columns []column.Column) []bool {
+	dynamic := make([]bool, len(columns))
+	for i, col := range columns {
+		if _, ok := col.(*column.ColumnJSONBytes); ok {
+			dynamic[i] = col.FieldData().GetIsDynamic()
+		}
+	}
+	return dynamic
+}
+
+// sliceColumns 返回各列[start, end)范围内的行
+// dynamic: dynamicColumns的结果，SDK的JSON列切片会丢失动态字段标记，需要重新设置
+func sliceColumns(columns []column.Column, dynamic []bool, start, end int) []column.Column {
+	chunk := make([]column.Column, len(columns))
+	for i, col := range columns {
+		chunk[i] = sliceColumn(col, start, end)
+		if json, ok := chunk[i].(*column.ColumnJSONBytes); ok && dynamic[i] {
+			chunk[i] = json.WithIsDynamic(true)
+		}
+	}
+	return chunk
+}
+
+// sliceColumn 返回列[start, end)范围内的行
+// SDK的Slice按行号截取值，对只保存非null值的compact模式可空列（由行数据转换而来）不正确，这类列按有效值重新构造
+func sliceColumn(col column.Column, start, end int) column.Column {
+	switch c := col.(type) {
+	case *column.ColumnBool:
+		return sliceNullable(c, column.NewNullableColumnBool, start, end)
+	case *column.ColumnInt8:
+		return sliceNullable(c, column.NewNullableColumnInt8, start, end)
+	case *column.ColumnInt16:
+		return sliceNullable(c, column.NewNullableColumnInt16, start, end)
+	case *column.ColumnInt32:
+		return sliceNullable(c, column.NewNullableColumnInt32, start, end)
+	case *column.ColumnInt64:
+		return sliceNullable(c, column.NewNullableColumnInt64, start, end)
+	case *column.ColumnFloat:
+		return sliceNullable(c, column.NewNullableColumnFloat, start, end)
+	case *column.ColumnDouble:
+		return sliceNullable(c, column.NewNullableColumnDouble, start, end)
+	case *column.ColumnVarChar:
+		return sliceNullable(c, column.NewNullableColumnVarChar, start, end)
+	case *column.ColumnString:
+		return sliceNullable(c, column.NewNullableColumnString, start, end)
+	case *column.ColumnTimestamptz:
+		return sliceNullable(c, column.NewNullableColumnTimestamptz, start, end)
+	case *column.ColumnJSONBytes:
+		return sliceNullable(c, column.NewNullableColumnJSONBytes, start, end)
+	case *column.ColumnBoolArray:
+		return sliceNullable(c, column.NewNullableColumnBoolArray, start, end)
+	case *column.ColumnInt8Array:
+		return sliceNullable(c, column.NewNullableColumnInt8Array, start, end)
+	case *column.ColumnInt16Array:
+		return sliceNullable(c, column.NewNullableColumnInt16Array, start, end)
+	case *column.ColumnInt32Array:
+		return sliceNullable(c, column.NewNullableColumnInt32Array, start, end)
+	case *column.ColumnInt64Array:
+		return sliceNullable(c, column.NewNullableColumnInt64Array, start, end)
+	case *column.ColumnFloatArray:
+		return sliceNullable(c, column.NewNullableColumnFloatArray, start, end)
+	case *column.ColumnDoubleArray:
+		return sliceNullable(c, column.NewNullableColumnDoubleArray, start, end)
+	case *column.ColumnVarCharArray:
+		return sliceNullable(c, column.NewNullableColumnVarCharArray, start, end)
+	}
+	return col.Slice(start, end)
+}
+
+// sliceNullable 截取可空列，compact模式（值的数量少于行数）时逐行追加值与null重新构造
+func sliceNullable[T any, C interface {
+	column.Column
+	Data() []T
+}](col C, create column.NullableColumnCreateFunc[T, C], start, end int) column.Column {
+	values := col.Data()
+	if !col.Nullable() || len(values) == col.Len() {
+		return col.Slice(start, end)
+	}
+
+	result, err := create(col.Name(), make([]T, 0, end-start), nil)
+	if err != nil {
+		return col.Slice(start, end)
+	}
+	result.SetNullable(true)
+	next := 0 // 下一个有效值在values中的位置
+	for i := 0; i < end; i++ {
+		isNull, err := col.IsNull(i)
+		if err != nil {
+			return col.Slice(start, end)
+		}
+		switch {
+		case isNull && i >= start:
+			err = result.AppendNull()
+		case !isNull && i >= start:
+			err = result.AppendValue(values[next])
+		}
+		if err != nil {
+			return col.Slice(start, end)
+		}
+		if !isNull {
+			next++
+		}
+	}
+	return result
+}
+
+// columnRows 返回列数据的行数
+func columnRows(columns []column.Column) int {
+	rows := 0
+	for _, col := range columns {
+		if col.Len() > rows {
+			rows = col.Len()
+		}
+	}
+	return rows
+}
+
+// columnSize 按元素宽度估算列数据序列化后的大小，避免为每次请求构造FieldData
+// 无法按宽度估算的列类型（数组、稀疏向量等）才构造FieldData计算
+func columnSize(col column.Column) int {
+	n := col.Len()
+	size := 0
+	if col.Nullable() {
+		size += n
+	}
+
+	if vec, ok := col.(interface{ Dim() int }); ok {
+		if width := vectorWidth(col.Type(), vec.Dim()); width > 0 {
+			return size + n*width
+		}
+	}
+	switch data := col.(type) {
+	case interface{ Data() []string }:
+		for _, v := range data.Data() {
+			size += 1 + protowire.SizeBytes(len(v))
+		}
+		return size
+	case interface{ Data() [][]byte }:
+		for _, v := range data.Data() {
+			size += 1 + protowire.SizeBytes(len(v))
+		}
+		return size
+	case interface{ Data() []int64 }:
+		return size + varintSize(data.Data())
+	case interface{ Data() []int32 }:
+		return size + varintSize(data.Data())
+	case interface{ Data() []int16 }:
+		return size + varintSize(data.Data())
+	case interface{ Data() []int8 }:
+		return size + varintSize(data.Data())
+	}
+
+	switch col.Type() {
+	case entity.FieldTypeBool:
+		return size + n
+	case entity.FieldTypeFloat:
+		return size + n*4
+	case entity.FieldTypeDouble:
+		return size + n*8
+	}
+	return proto.Size(col.FieldData())
+}
+
+// varintSize 返回整数按varint编码后的总字节数
+func varintSize[T int8 | int16 | int32 | int64](values []T) int {
+	size := 0
+	for _, v := range values {
+		size += protowire.SizeVarint(uint64(v))
+	}
+	return size
+}
+
+// vectorWidth 返回定长向量每条的字节数，非定长向量返回0
+func vectorWidth(fieldType entity.FieldType, dim int) int {
+	switch fieldType {
+	case entity.FieldTypeFloatVector:
+		return dim * 4
+	case entity.FieldTypeFloat16Vector, entity.FieldTypeBFloat16Vector:
+		return dim * 2
+	case entity.FieldTypeBinaryVector:
+		return dim / 8
+	case entity.FieldTypeInt8Vector:
+		return dim
+	}
+	return 0
+}
+
+// splitVectors 按查询向量的估算大小与数量拆分搜索请求
+// maxSize <= 0 表示不限制大小，maxNQ <= 0 表示不限制数量
+func splitVectors(vectors []entity.Vector, maxSize int, maxNQ int) [][]entity.Vector {
+	var chunks [][]entity.Vector
+	start, size := 0, 0
+	for i, v := range vectors {
+		vs := vectorWidth(v.FieldType(), v.Dim())
+		if vs == 0 {
+			vs = len(v.Serialize())
+		}
+		full := (maxNQ > 0 && i-start >= maxNQ) || (maxSize > 0 && size+vs > maxSize)
+		if full && i > start {
+			chunks = append(chunks, vectors[start:i])
+			start, size = i, 0
+		}
+		size += vs
+	}
+	return append(chunks, vectors[start:])
+}
+
+// appendColumn 将src的数据追加到dst，用于合并拆分后各段返回的主键
+func appendColumn(dst, src column.Column) error {
+	if dst == nil || src == nil {
+		return errors.New("nil column")
+	}
+	for i := 0; i < src.Len(); i++ {
+		value, err := src.Get(i)
+		if err != nil {
+			return err
+		}
+		if err := dst.AppendValue(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
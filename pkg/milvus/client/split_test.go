@@ -0,0 +1,144 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestSplitColumns 测试按请求大小拆分列数据
+func TestSplitColumns(t *testing.T) {
+	ids := make([]int64, 100)
+	vectors := make([][]float32, 100)
+	meta := make([][]byte, 100)
+	for i := range ids {
+		ids[i] = int64(i)
+		vectors[i] = make([]float32, 64)
+		meta[i] = []byte(`{"k":1}`)
+	}
+	columns := []column.Column{
+		column.NewColumnInt64("id", ids),
+		column.NewColumnFloatVector("vector", 64, vectors),
+		column.NewColumnJSONBytes("", meta).WithIsDynamic(true),
+	}
+
+	t.Run("未超过上限", func(t *testing.T) {
+		assert.Len(t, splitColumns(columns, 0), 1)
+		assert.Len(t, splitColumns(columns, 1<<20), 1)
+	})
+
+	t.Run("超过上限", func(t *testing.T) {
+		// 每行约260字节，上限4KB时每段约15行
+		chunks := splitColumns(columns, 4096)
+		require.Greater(t, len(chunks), 1)
+
+		total := 0
+		for _, chunk := range chunks {
+			require.Len(t, chunk, 3)
+			assert.Equal(t, chunk[0].Len(), chunk[1].Len())
+			assert.True(t, chunk[2].FieldData().GetIsDynamic(), "拆分后应保留动态字段标记")
+			total += chunk[0].Len()
+		}
+		assert.Equal(t, 100, total)
+
+		last := chunks[len(chunks)-1][0]
+		id, err := last.GetAsInt64(last.Len() - 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(99), id)
+	})
+
+	t.Run("单行超过上限", func(t *testing.T) {
+		chunks := splitColumns(columns, 1)
+		assert.Len(t, chunks, 100)
+	})
+
+	t.Run("拆分可空列", func(t *testing.T) {
+		// 由行数据转换的可空列只保存非null值
+		nullable := column.NewColumnInt64("score", nil)
+		nullable.SetNullable(true)
+		for i := range 100 {
+			if i%3 == 0 {
+				require.NoError(t, nullable.AppendNull())
+			} else {
+				require.NoError(t, nullable.AppendValue(int64(i)))
+			}
+		}
+		cols := []column.Column{columns[0], nullable}
+
+		chunks := splitColumns(cols, 100)
+		require.Greater(t, len(chunks), 1)
+		row := 0
+		for _, chunk := range chunks {
+			require.Equal(t, chunk[0].Len(), chunk[1].Len())
+			for i := 0; i < chunk[1].Len(); i++ {
+				isNull, err := chunk[1].IsNull(i)
+				require.NoError(t, err)
+				assert.Equal(t, row%3 == 0, isNull, "行%d", row)
+				if !isNull {
+					value, err := chunk[1].GetAsInt64(i)
+					require.NoError(t, err)
+					assert.Equal(t, int64(row), value)
+				}
+				row++
+			}
+		}
+		assert.Equal(t, 100, row)
+	})
+
+	t.Run("按元素宽度估算大小", func(t *testing.T) {
+		names := make([]string, 100)
+		binary := make([][]byte, 100)
+		for i := range names {
+			names[i] = fmt.Sprintf("name-%d", i)
+			binary[i] = make([]byte, 16)
+		}
+		cols := append([]column.Column{
+			column.NewColumnVarChar("name", names),
+			column.NewColumnBinaryVector("binary", 128, binary),
+		}, columns...)
+		for _, col := range cols {
+			actual := proto.Size(col.FieldData())
+			assert.InDelta(t, actual, columnSize(col), float64(actual)/10+16, col.Name())
+		}
+	})
+}
+
+// TestSplitVectors 测试按数量与大小拆分查询向量
+func TestSplitVectors(t *testing.T) {
+	vectors := make([]entity.Vector, 10)
+	for i := range vectors {
+		vectors[i] = entity.FloatVector(make([]float32, 4)) // 16字节
+	}
+
+	assert.Len(t, splitVectors(vectors, 0, 0), 1)
+
+	chunks := splitVectors(vectors, 0, 4)
+	require.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], 4)
+	assert.Len(t, chunks[2], 2)
+
+	chunks = splitVectors(vectors, 48, 0)
+	require.Len(t, chunks, 4)
+	assert.Len(t, chunks[0], 3)
+
+	chunks = splitVectors(vectors, 1, 0)
+	assert.Len(t, chunks, 10, "单个向量超过上限时逐个发送")
+}
+
+// TestAppendColumn 测试合并主键列
+func TestAppendColumn(t *testing.T) {
+	dst := column.NewColumnInt64("id", []int64{1, 2})
+	require.NoError(t, appendColumn(dst, column.NewColumnInt64("id", []int64{3})))
+	assert.Equal(t, []int64{1, 2, 3}, dst.Data())
+
+	varchar := column.NewColumnVarChar("id", []string{"a"})
+	require.NoError(t, appendColumn(varchar, column.NewColumnVarChar("id", []string{"b"})))
+	assert.Equal(t, []string{"a", "b"}, varchar.Data())
+
+	assert.Error(t, appendColumn(nil, varchar))
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTimeouts 返回各操作类别的默认超时时间
+// 元数据变更包含LoadCollection等需要等待服务端完成的操作，因此超时时间较长
+func DefaultTimeouts() map[OperationClass]time.Duration {
+	return map[OperationClass]time.Duration{
+		OpRead:  30 * time.Second,
+		OpWrite: time.Minute,
+		OpDDL:   10 * time.Minute,
+	}
+}
+
+// timeoutKey 单次调用超时时间在上下文中的键
+type timeoutKey struct{}
+
+// ContextWithTimeout 为单次调用指定超时时间，覆盖Options中按操作类别配置的默认超时
+// ctx: 调用方的上下文
+// timeout: 超时时间，<= 0表示该调用不使用默认超时，只受ctx本身控制
+// 返回值: 携带超时设置的上下文，传给Client的方法使用
+func ContextWithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
+// withTimeout 为操作设置超时时间，超时时间覆盖重试在内的整个操作
+// 通过ContextWithTimeout指定的超时优先；否则ctx没有截止时间时使用该操作类别的默认超时
+func (c *client) withTimeout(ctx context.Context, class OperationClass) (context.Context, context.CancelFunc) {
+	timeout, ok := ctx.Value(timeoutKey{}).(time.Duration)
+	if !ok {
+		if _, hasDeadline := ctx.Deadline(); hasDeadline {
+			return ctx, func() {}
+		}
+		timeout = c.options.Timeouts[class]
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deadlineOf 执行操作并返回fn收到的ctx剩余时间，没有截止时间时返回0
+func deadlineOf(t *testing.T, c *client, ctx context.Context, class OperationClass) time.Duration {
+	var remaining time.Duration
+	err := c.invoke(ctx, &OperationInfo{Name: "Op", Class: class}, func(ctx context.Context) error {
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining = time.Until(deadline)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return remaining
+}
+
+// TestTimeouts 测试默认超时
+func TestTimeouts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("按操作类别设置默认超时", func(t *testing.T) {
+		c := newRetryTestClient(WithTimeout(OpRead, time.Second))
+		assert.InDelta(t, time.Second, deadlineOf(t, c, ctx, OpRead), float64(100*time.Millisecond))
+		assert.InDelta(t, 10*time.Minute, deadlineOf(t, c, ctx, OpDDL), float64(time.Second))
+	})
+
+	t.Run("关闭某一类别的默认超时", func(t *testing.T) {
+		c := newRetryTestClient(WithTimeout(OpWrite, 0))
+		assert.Zero(t, deadlineOf(t, c, ctx, OpWrite))
+	})
+
+	t.Run("保留调用方的截止时间", func(t *testing.T) {
+		c := newRetryTestClient(WithTimeout(OpRead, time.Second))
+		callerCtx, cancel := context.WithTimeout(ctx, time.Hour)
+		defer cancel()
+		assert.InDelta(t, time.Hour, deadlineOf(t, c, callerCtx, OpRead), float64(time.Second))
+	})
+
+	t.Run("单次调用覆盖", func(t *testing.T) {
+		c := newRetryTestClient(WithTimeout(OpDDL, time.Second))
+		assert.InDelta(t, 5*time.Minute, deadlineOf(t, c, ContextWithTimeout(ctx, 5*time.Minute), OpDDL), float64(time.Second))
+		assert.Zero(t, deadlineOf(t, c, ContextWithTimeout(ctx, 0), OpDDL))
+	})
+
+	t.Run("超时包含重试", func(t *testing.T) {
+		policy := fastPolicy(100)
+		policy.InitialBackoff, policy.MaxBackoff = 10*time.Millisecond, 10*time.Millisecond
+		c := newRetryTestClient(WithTimeout(OpRead, 50*time.Millisecond), WithRetryPolicy(OpRead, policy))
+
+		calls := 0
+		err := c.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			calls++
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.ErrorIs(t, err, ErrTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TLSConfig TLS与mTLS配置，非nil时启用TLS
+//
+// 证书文件使用PEM格式。ReloadInterval大于0时，每次TLS握手前检查证书文件是否变化（间隔不小于ReloadInterval），
+// 变化后使用新证书，证书轮换无需重建客户端；已建立的连接不受影响，新证书在重连时生效。
+// 新文件无法解析时（例如正在写入）继续使用旧证书，下次检查时重试。
+type TLSConfig struct {
+	CAFile             string        // 校验服务端证书的CA证书文件，为空时使用系统根证书
+	CertFile           string        // 客户端证书文件，与KeyFile一起用于mTLS
+	KeyFile            string        // 客户端私钥文件
+	ServerName         string        // 校验服务端证书使用的名称，为空时使用地址中的主机名
+	MinVersion         string        // 最低TLS版本，1.0、1.1、1.2或1.3，为空时使用Go的默认值
+	InsecureSkipVerify bool          // 是否跳过服务端证书校验，仅用于测试
+	ReloadInterval     time.Duration // 检查证书文件变化的最小间隔，0表示不重新加载
+
+	Config *tls.Config // 基础TLS配置，上面的字段覆盖其中的对应配置，nil表示使用空配置
+}
+
+// tlsVersions TLS版本名称
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion 解析"1.2"、"1.3"这样的TLS版本
+func parseTLSVersion(v string) (uint16, error) {
+	if version, ok := tlsVersions[strings.TrimPrefix(strings.ToUpper(v), "TLS")]; ok {
+		return version, nil
+	}
+	return 0, errors.Errorf("invalid TLS version %q, expected 1.0, 1.1, 1.2 or 1.3", v)
+}
+
+// formatTLSVersion 返回TLS版本名称，与parseTLSVersion对应
+func formatTLSVersion(version uint16) string {
+	for name, v := range tlsVersions {
+		if v == version {
+			return name
+		}
+	}
+	return strconv.Itoa(int(version))
+}
+
+// build 创建连接使用的tls.Config，加载失败时返回错误
+// address: 服务地址，未设置ServerName时使用其中的主机名校验服务端证书
+func (c *TLSConfig) build(address string) (*tls.Config, error) {
+	cfg := &tls.Config{}
+	if c.Config != nil {
+		cfg = c.Config.Clone()
+	}
+	if c.ServerName != "" {
+		cfg.ServerName = c.ServerName
+	}
+	if cfg.ServerName == "" {
+		// 握手时IP地址不会作为SNI发送，ConnectionState中没有名称，需要在这里确定校验使用的名称
+		cfg.ServerName = addressHost(address)
+	}
+	if c.MinVersion != "" {
+		version, err := parseTLSVersion(c.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		cfg.MinVersion = version
+	}
+	if c.InsecureSkipVerify {
+		cfg.InsecureSkipVerify = true
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errors.New("TLS cert file and key file must be set together")
+	}
+	if c.CAFile == "" && c.CertFile == "" {
+		return cfg, nil
+	}
+
+	loader := &certLoader{config: c}
+	if err := loader.load(); err != nil {
+		return nil, err
+	}
+	loader.checked = time.Now()
+	if c.CertFile != "" {
+		cfg.Certificates = nil
+		cfg.GetClientCertificate = loader.clientCertificate
+	}
+	if c.CAFile != "" && !cfg.InsecureSkipVerify {
+		// CA证书可能重新加载，由VerifyConnection使用当前的CA校验服务端证书，跳过标准库使用固定RootCAs的校验
+		verify, serverName := cfg.VerifyConnection, cfg.ServerName
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if err := loader.verify(cs, serverName); err != nil {
+				return err
+			}
+			if verify != nil {
+				return verify(cs)
+			}
+			return nil
+		}
+	}
+	return cfg, nil
+}
+
+// addressHost 返回服务地址中的主机名，地址可以带有http://或https://前缀
+func addressHost(address string) string {
+	if i := strings.Index(address, "://"); i >= 0 {
+		address = address[i+3:]
+	}
+	address = strings.TrimSuffix(address, "/")
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
+
+// certLoader 加载并按需重新加载证书文件
+type certLoader struct {
+	config *TLSConfig
+
+	mu      sync.Mutex
+	checked time.Time        // 上次检查文件的时间
+	caPEM   []byte           // 当前CA文件内容
+	certPEM []byte           // 当前证书文件内容
+	keyPEM  []byte           // 当前私钥文件内容
+	roots   *x509.CertPool   // 当前CA
+	cert    *tls.Certificate // 当前客户端证书
+}
+
+// load 读取并解析证书文件，内容未变化时不重新解析
+func (l *certLoader) load() error {
+	c := l.config
+	var caPEM, certPEM, keyPEM []byte
+	var err error
+	if c.CAFile != "" {
+		if caPEM, err = os.ReadFile(c.CAFile); err != nil {
+			return errors.Wrap(err, "failed to read TLS CA file")
+		}
+	}
+	if c.CertFile != "" {
+		if certPEM, err = os.ReadFile(c.CertFile); err != nil {
+			return errors.Wrap(err, "failed to read TLS cert file")
+		}
+		if keyPEM, err = os.ReadFile(c.KeyFile); err != nil {
+			return errors.Wrap(err, "failed to read TLS key file")
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if c.CAFile != "" && !bytes.Equal(caPEM, l.caPEM) {
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caPEM) {
+			return errors.Errorf("no valid certificates in TLS CA file %s", c.CAFile)
+		}
+		l.roots, l.caPEM = roots, caPEM
+	}
+	if c.CertFile != "" && (!bytes.Equal(certPEM, l.certPEM) || !bytes.Equal(keyPEM, l.keyPEM)) {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return errors.Wrap(err, "invalid TLS cert or key file")
+		}
+		l.cert, l.certPEM, l.keyPEM = &cert, certPEM, keyPEM
+	}
+	return nil
+}
+
+// reload 距上次检查超过ReloadInterval时重新加载证书文件，失败时继续使用当前证书
+func (l *certLoader) reload() {
+	if l.config.ReloadInterval <= 0 {
+		return
+	}
+	l.mu.Lock()
+	due := time.Since(l.checked) >= l.config.ReloadInterval
+	if due {
+		l.checked = time.Now()
+	}
+	l.mu.Unlock()
+	if due {
+		_ = l.load()
+	}
+}
+
+// clientCertificate 实现tls.Config.GetClientCertificate，返回当前客户端证书
+func (l *certLoader) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	l.reload()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cert, nil
+}
+
+// verify 使用当前CA校验服务端证书链与名称
+func (l *certLoader) verify(cs tls.ConnectionState, serverName string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server did not provide a certificate")
+	}
+	l.reload()
+	l.mu.Lock()
+	roots := l.roots
+	l.mu.Unlock()
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       serverName,
+		Intermediates: intermediates,
+	})
+	return err
+}
@@ -0,0 +1,274 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// testCA 测试用的证书签发机构
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// newTestCA 生成自签名CA
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue 签发证书，返回PEM格式的证书与私钥
+// dnsNames: 服务端证书的名称，为空时签发客户端证书
+func (ca *testCA) issue(t *testing.T, name string, dnsNames ...string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		DNSNames:     dnsNames,
+	}
+	if len(dnsNames) > 0 {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile 写入测试目录中的文件并返回路径
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// startTLSServer 启动要求客户端证书的gRPC服务，服务端证书签发给milvus.internal
+func startTLSServer(t *testing.T, serverCA, clientCA *testCA, maxVersion uint16) string {
+	certPEM, keyPEM := serverCA.issue(t, "server", "milvus.internal")
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCA.cert)
+
+	return testserver.Start(t, testserver.WithServerOptions(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MaxVersion:   maxVersion,
+	}))))
+}
+
+// TestTLS 测试TLS与mTLS配置
+func TestTLS(t *testing.T) {
+	serverCA := newTestCA(t, "server-ca")
+	clientCA := newTestCA(t, "client-ca")
+	addr := startTLSServer(t, serverCA, clientCA, 0)
+
+	dir := t.TempDir()
+	caFile := writeFile(t, dir, "ca.pem", serverCA.pem)
+	certPEM, keyPEM := clientCA.issue(t, "client")
+	certFile := writeFile(t, dir, "client.pem", certPEM)
+	keyFile := writeFile(t, dir, "client-key.pem", keyPEM)
+
+	// connect 连接服务端，握手失败时在超时后返回错误
+	connect := func(opts ...Option) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		all := append([]Option{WithAddress(addr), WithDisableConn(true)}, opts...)
+		cli, err := NewWithOptions(ctx, all...)
+		if err == nil {
+			_ = cli.Close()
+		}
+		return err
+	}
+
+	t.Run("使用证书文件的mTLS", func(t *testing.T) {
+		assert.NoError(t, connect(WithTLSFiles(caFile, certFile, keyFile), WithTLSServerName("milvus.internal")))
+	})
+
+	t.Run("使用tls.Config", func(t *testing.T) {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		require.NoError(t, err)
+		roots := x509.NewCertPool()
+		roots.AddCert(serverCA.cert)
+		assert.NoError(t, connect(WithTLSConfig(&tls.Config{
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{cert},
+			ServerName:   "milvus.internal",
+		})))
+	})
+
+	t.Run("服务端名称不匹配", func(t *testing.T) {
+		assert.Error(t, connect(WithTLSFiles(caFile, certFile, keyFile)))
+	})
+
+	t.Run("CA不匹配", func(t *testing.T) {
+		otherCA := writeFile(t, t.TempDir(), "ca.pem", clientCA.pem)
+		assert.Error(t, connect(WithTLSFiles(otherCA, certFile, keyFile), WithTLSServerName("milvus.internal")))
+	})
+
+	t.Run("未提供客户端证书", func(t *testing.T) {
+		assert.Error(t, connect(WithTLSFiles(caFile, "", ""), WithTLSServerName("milvus.internal")))
+	})
+
+	t.Run("最低TLS版本", func(t *testing.T) {
+		tls12 := startTLSServer(t, serverCA, clientCA, tls.VersionTLS12)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err := NewWithOptions(ctx, WithAddress(tls12), WithDisableConn(true),
+			WithTLSFiles(caFile, certFile, keyFile), WithTLSServerName("milvus.internal"), WithTLSMinVersion(tls.VersionTLS13))
+		assert.Error(t, err)
+	})
+
+	t.Run("通过URI配置", func(t *testing.T) {
+		uri := "milvus://" + addr + "?tls_ca_file=" + caFile + "&tls_cert_file=" + certFile +
+			"&tls_key_file=" + keyFile + "&tls_server_name=milvus.internal&tls_min_version=1.2"
+		o, err := ParseURI(uri)
+		require.NoError(t, err)
+		assert.True(t, o.EnableTLSAuth, "tls参数隐含启用TLS")
+		assert.Equal(t, "1.2", o.TLS.MinVersion)
+		assert.NoError(t, connect(WithURI(uri)))
+	})
+
+	t.Run("无效配置", func(t *testing.T) {
+		tests := []struct {
+			name string
+			opt  Option
+			want string
+		}{
+			{"证书文件不存在", WithTLSFiles(filepath.Join(dir, "missing.pem"), "", ""), "failed to read TLS CA file"},
+			{"CA文件无效", WithTLSFiles(keyFile, "", ""), "no valid certificates"},
+			{"缺少私钥", WithTLSFiles("", certFile, ""), "must be set together"},
+			{"私钥不匹配", WithTLSFiles("", certFile, caFile), "invalid TLS cert or key file"},
+			{"TLS版本无效", func(o *Options) { tlsConfig(o).MinVersion = "2.0" }, "invalid TLS version"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := NewWithOptions(context.Background(), WithAddress(addr), tt.opt)
+				assert.ErrorContains(t, err, "failed to load TLS config")
+				assert.ErrorContains(t, err, tt.want)
+			})
+		}
+	})
+
+	t.Run("选项不修改共享的TLS配置", func(t *testing.T) {
+		base := DefaultOptions()
+		WithTLSFiles(caFile, "", "")(base)
+		copied := *base
+		WithTLSServerName("milvus.internal")(&copied)
+		assert.Empty(t, base.TLS.ServerName)
+		assert.Equal(t, caFile, copied.TLS.CAFile)
+	})
+}
+
+// TestTLSReload 测试证书文件变化后重新加载
+func TestTLSReload(t *testing.T) {
+	oldCA := newTestCA(t, "old-ca")
+	newCA := newTestCA(t, "new-ca")
+	dir := t.TempDir()
+	caFile := writeFile(t, dir, "ca.pem", oldCA.pem)
+	certPEM, keyPEM := oldCA.issue(t, "client-v1")
+	certFile := writeFile(t, dir, "client.pem", certPEM)
+	keyFile := writeFile(t, dir, "client-key.pem", keyPEM)
+
+	cfg, err := (&TLSConfig{
+		CAFile:         caFile,
+		CertFile:       certFile,
+		KeyFile:        keyFile,
+		ReloadInterval: 20 * time.Millisecond,
+	}).build("milvus.internal:19530")
+	require.NoError(t, err)
+
+	// 服务端使用新CA签发的证书，并记录客户端证书的名称
+	serverPEM, serverKey := newCA.issue(t, "server", "milvus.internal")
+	serverCert, err := tls.X509KeyPair(serverPEM, serverKey)
+	require.NoError(t, err)
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequestClientCert,
+	})
+	require.NoError(t, err)
+	defer lis.Close()
+	clientNames := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := conn.(*tls.Conn)
+			if tlsConn.Handshake() == nil && len(tlsConn.ConnectionState().PeerCertificates) > 0 {
+				clientNames <- tlsConn.ConnectionState().PeerCertificates[0].Subject.CommonName
+			}
+			_ = conn.Close()
+		}
+	}()
+	handshake := func() error {
+		conn, err := tls.Dial("tcp", lis.Addr().String(), cfg)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	require.Error(t, handshake(), "旧CA不信任服务端证书")
+
+	// 轮换CA与客户端证书
+	writeFile(t, dir, "ca.pem", newCA.pem)
+	certPEM, keyPEM = newCA.issue(t, "client-v2")
+	writeFile(t, dir, "client.pem", certPEM)
+	writeFile(t, dir, "client-key.pem", keyPEM)
+
+	require.Eventually(t, func() bool { return handshake() == nil }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "client-v2", <-clientNames, "使用新的客户端证书")
+
+	t.Run("新文件无效时继续使用当前证书", func(t *testing.T) {
+		writeFile(t, dir, "ca.pem", []byte("not a certificate"))
+		writeFile(t, dir, "client.pem", []byte("not a certificate"))
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, handshake())
+		assert.Equal(t, "client-v2", <-clientNames)
+	})
+}
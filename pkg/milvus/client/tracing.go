@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// tracerName 创建span使用的instrumentation名称
+const tracerName = "github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+
+// tracingAttributes OperationInfo.Args中记录到span的参数及对应的属性名
+var tracingAttributes = map[string]string{
+	"partition":   "milvus.partition",
+	"partitions":  "milvus.partitions",
+	"field":       "milvus.field",
+	"alias":       "milvus.alias",
+	"rows":        "milvus.rows",
+	"nq":          "milvus.nq",
+	"topK":        "milvus.top_k",
+	"limit":       "milvus.limit",
+	"vectorField": "milvus.vector_field",
+	"metricType":  "milvus.metric_type",
+}
+
+// tracingInterceptor 为每个操作创建client类型的span
+// span名称为"milvus.<操作名称>"，记录数据库、集合、分区、topK、行数与结果行数等属性，失败时记录错误
+func tracingInterceptor(tp trace.TracerProvider) Interceptor {
+	tracer := tp.Tracer(tracerName)
+	return func(ctx context.Context, info *OperationInfo, invoker Invoker) error {
+		attrs := []attribute.KeyValue{
+			attribute.String("db.system", "milvus"),
+			attribute.String("db.operation", info.Name),
+			attribute.String("milvus.operation.class", info.Class.String()),
+		}
+		if info.Database != "" {
+			attrs = append(attrs, attribute.String("db.name", info.Database))
+		}
+		if info.Collection != "" {
+			attrs = append(attrs, attribute.String("db.collection.name", info.Collection))
+		}
+		for arg, key := range tracingAttributes {
+			if attr, ok := toAttribute(key, info.Args[arg]); ok {
+				attrs = append(attrs, attr)
+			}
+		}
+
+		ctx, span := tracer.Start(ctx, "milvus."+info.Name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		err := invoker(ctx, info)
+		span.SetAttributes(
+			attribute.Int("milvus.result_rows", info.ResultRows),
+			attribute.Int("milvus.attempts", info.Attempts),
+		)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return err
+	}
+}
+
+// toAttribute 将参数值转换为span属性，空值与不支持的类型返回false
+func toAttribute(key string, value any) (attribute.KeyValue, bool) {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v), v != ""
+	case int:
+		return attribute.Int(key, v), true
+	case []string:
+		return attribute.StringSlice(key, v), len(v) > 0
+	default:
+		return attribute.KeyValue{}, false
+	}
+}
+
+// propagatorOrGlobal 返回配置的传播器，未配置时使用调用时的全局传播器
+func propagatorOrGlobal(p propagation.TextMapPropagator) propagation.TextMapPropagator {
+	if p != nil {
+		return p
+	}
+	return otel.GetTextMapPropagator()
+}
+
+// injectTraceContext 将追踪上下文写入gRPC请求元数据
+func injectTraceContext(ctx context.Context, p propagation.TextMapPropagator) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	propagatorOrGlobal(p).Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// tracingUnaryInterceptor 在一元调用中传播追踪上下文
+func tracingUnaryInterceptor(p propagation.TextMapPropagator) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(injectTraceContext(ctx, p), method, req, reply, cc, opts...)
+	}
+}
+
+// tracingStreamInterceptor 在流式调用中传播追踪上下文
+func tracingStreamInterceptor(p propagation.TextMapPropagator) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(injectTraceContext(ctx, p), desc, cc, method, opts...)
+	}
+}
+
+// metadataCarrier 适配gRPC元数据的TextMapCarrier
+type metadataCarrier metadata.MD
+
+// Get 返回键对应的第一个值
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set 设置键的值
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys 返回所有键
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// spanAttributes 将span属性转换为map便于断言
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value, len(span.Attributes))
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+// TestTracing 测试为客户端操作创建span
+func TestTracing(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("记录操作属性", func(t *testing.T) {
+		exporter := tracetest.NewInMemoryExporter()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		c := newRetryTestClient(WithDatabase("db1"), WithTracing(tp, nil))
+
+		op := &OperationInfo{
+			Name:       "Search",
+			Class:      OpRead,
+			Collection: "c1",
+			Args:       map[string]any{"partitions": []string{"p1", "p2"}, "nq": 2, "topK": 10, "expr": "id > 0"},
+		}
+		err := c.invoke(ctx, op, func(ctx context.Context) error {
+			assert.True(t, trace.SpanFromContext(ctx).SpanContext().IsValid())
+			op.ResultRows = 20
+			return nil
+		})
+		require.NoError(t, err)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "milvus.Search", spans[0].Name)
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+		assert.Equal(t, otelcodes.Unset, spans[0].Status.Code)
+
+		attrs := spanAttributes(spans[0])
+		assert.Equal(t, "milvus", attrs["db.system"].AsString())
+		assert.Equal(t, "Search", attrs["db.operation"].AsString())
+		assert.Equal(t, "db1", attrs["db.name"].AsString())
+		assert.Equal(t, "c1", attrs["db.collection.name"].AsString())
+		assert.Equal(t, []string{"p1", "p2"}, attrs["milvus.partitions"].AsStringSlice())
+		assert.Equal(t, int64(2), attrs["milvus.nq"].AsInt64())
+		assert.Equal(t, int64(10), attrs["milvus.top_k"].AsInt64())
+		assert.Equal(t, int64(20), attrs["milvus.result_rows"].AsInt64())
+		assert.Equal(t, int64(1), attrs["milvus.attempts"].AsInt64())
+		assert.NotContains(t, attrs, attribute.Key("milvus.expr"), "过滤表达式可能包含业务数据，不记录")
+	})
+
+	t.Run("记录错误与重试次数", func(t *testing.T) {
+		exporter := tracetest.NewInMemoryExporter()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		c := newRetryTestClient(WithTracing(tp, nil), WithRetryPolicy(OpWrite, fastPolicy(2)))
+
+		op := &OperationInfo{Name: "Insert", Class: OpWrite, Collection: "c1", Args: map[string]any{"partition": "p1", "rows": 100}}
+		err := c.invoke(ctx, op, func(ctx context.Context) error {
+			return merr.ErrServiceUnavailable
+		})
+		assert.ErrorIs(t, err, ErrUnavailable)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, otelcodes.Error, spans[0].Status.Code)
+		require.Len(t, spans[0].Events, 1)
+		assert.Equal(t, "exception", spans[0].Events[0].Name)
+
+		attrs := spanAttributes(spans[0])
+		assert.Equal(t, "p1", attrs["milvus.partition"].AsString())
+		assert.Equal(t, int64(100), attrs["milvus.rows"].AsInt64())
+		assert.Equal(t, int64(2), attrs["milvus.attempts"].AsInt64())
+	})
+
+	t.Run("未启用时不创建span", func(t *testing.T) {
+		c := newRetryTestClient()
+		err := c.invoke(ctx, &OperationInfo{Name: "Query", Class: OpRead}, func(ctx context.Context) error {
+			assert.False(t, trace.SpanFromContext(ctx).SpanContext().IsValid())
+			return nil
+		})
+		require.NoError(t, err)
+	})
+}
+
+// TestTracingPropagation 测试通过gRPC元数据传播追踪上下文
+func TestTracingPropagation(t *testing.T) {
+	var mu sync.Mutex
+	var traceparents []string
+	addr := testserver.Start(t, testserver.WithHandler(func(srv any, stream grpc.ServerStream) error {
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		mu.Lock()
+		traceparents = append(traceparents, md.Get("traceparent")...)
+		mu.Unlock()
+		return status.Error(codes.Unimplemented, "unknown service")
+	}))
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	connectCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cli, err := NewWithOptions(connectCtx,
+		WithAddress(addr),
+		WithDisableConn(true),
+		WithRetryPolicy(OpRead, nil),
+		WithTracing(tp, propagation.TraceContext{}),
+	)
+	require.NoError(t, err)
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = cli.HasCollection(ctx, "c1")
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "milvus.HasCollection", spans[0].Name)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, traceparents)
+	assert.Contains(t, traceparents[len(traceparents)-1], spans[0].SpanContext.TraceID().String())
+}
@@ -0,0 +1,203 @@
+package milvus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+)
+
+// Config 连接池的配置文件内容
+//
+// 配置文件包含pool与clients两部分，pool对应PoolOptions，clients按名称对应每个客户端的client.Options，
+// 配置键为字段名的蛇形命名（例如DBName为db_name，KeepaliveTime为keepalive_time），
+// 按操作类别配置的字段（retry_policies、rate_limits、timeouts）以read、write、ddl为键，
+// 列表（例如endpoints）整体替换原值，
+// 时间间隔使用"5s"、"1m30s"这样的字符串。日志、追踪、指标、拦截器等无法用配置文件表示的选项
+// 通过WithPoolMetrics与WithClientOptions在代码中设置。
+//
+// 字符串中的${VAR}会替换为环境变量的值，变量未设置时报错；${VAR:-default}在变量未设置或为空时使用默认值；$$表示字面量$。
+type Config struct {
+	Pool    *PoolOptions             // 默认连接池选项应用配置文件后的结果
+	Clients map[string]*ClientConfig // 按名称的客户端配置
+}
+
+// ClientConfig 配置文件中一个客户端的配置
+type ClientConfig struct {
+	Options *client.Options // 默认选项应用配置文件后的结果，用于查看生效配置
+
+	values map[string]any // 替换环境变量后的原始配置
+}
+
+// Option 返回应用该客户端配置的选项，只覆盖配置文件中出现的字段
+func (c *ClientConfig) Option() client.Option {
+	values := c.values
+	return func(o *client.Options) {
+		// 配置在加载时已经校验过，这里不会出错
+		_ = decodeValue(reflect.ValueOf(o).Elem(), values, "")
+	}
+}
+
+// LoadConfig 读取配置文件，按扩展名识别格式：.yaml、.yml、.json、.toml
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read pool config")
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	cfg, err := ParseConfig(data, format)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid pool config %s", path)
+	}
+	return cfg, nil
+}
+
+// ParseConfig 解析配置内容
+// format: 配置格式，yaml（或yml）、json、toml
+func ParseConfig(data []byte, format string) (*Config, error) {
+	root := map[string]any{}
+	var err error
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &root)
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&root)
+	case "toml":
+		_, err = toml.Decode(string(data), &root)
+	default:
+		return nil, errors.Errorf("unsupported config format %q, expected yaml, json or toml", format)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s config", format)
+	}
+	if root == nil {
+		// 空的YAML文件
+		root = map[string]any{}
+	}
+	if _, err := interpolate("", root); err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{Pool: DefaultPoolOptions(), Clients: make(map[string]*ClientConfig)}
+	for _, key := range sortedKeys(root) {
+		switch key {
+		case "pool":
+			if err := decodeValue(reflect.ValueOf(cfg.Pool).Elem(), root[key], key); err != nil {
+				return nil, err
+			}
+		case "clients":
+			clients, ok := root[key].(map[string]any)
+			if !ok {
+				return nil, configErrorf(key, "expected a table of named clients, got %s", describe(root[key]))
+			}
+			for _, name := range sortedKeys(clients) {
+				cc, err := parseClientConfig(joinKey(key, name), clients[name])
+				if err != nil {
+					return nil, err
+				}
+				cfg.Clients[name] = cc
+			}
+		default:
+			return nil, configErrorf(key, "unknown key, expected pool or clients")
+		}
+	}
+	return cfg, nil
+}
+
+// parseClientConfig 解析并校验一个客户端的配置
+func parseClientConfig(key string, v any) (*ClientConfig, error) {
+	values, ok := v.(map[string]any)
+	if !ok {
+		return nil, configErrorf(key, "expected a table, got %s", describe(v))
+	}
+	options := client.DefaultOptions()
+	if err := decodeValue(reflect.ValueOf(options).Elem(), values, key); err != nil {
+		return nil, err
+	}
+	if err := validateClientOptions(key, options); err != nil {
+		return nil, err
+	}
+	return &ClientConfig{Options: options, values: values}, nil
+}
+
+// validateClientOptions 校验类型正确但取值无效的配置
+func validateClientOptions(key string, o *client.Options) error {
+	if strings.TrimSpace(o.Address) == "" {
+		return configErrorf(joinKey(key, "address"), "must not be empty")
+	}
+	for i, ep := range o.Endpoints {
+		if strings.TrimSpace(ep.Address) == "" {
+			return configErrorf(joinKey(key, fmt.Sprintf("endpoints[%d].address", i)), "must not be empty")
+		}
+	}
+	if o.Jitter > 1 {
+		return configErrorf(joinKey(key, "jitter"), "must be between 0 and 1, got %v", o.Jitter)
+	}
+	for class, policy := range o.RetryPolicies {
+		if policy != nil && policy.Jitter > 1 {
+			return configErrorf(joinKey(key, "retry_policies."+class.String()+".jitter"), "must be between 0 and 1, got %v", policy.Jitter)
+		}
+	}
+	if cb := o.CircuitBreaker; cb != nil {
+		if cb.ErrorRate > 1 {
+			return configErrorf(joinKey(key, "circuit_breaker.error_rate"), "must be between 0 and 1, got %v", cb.ErrorRate)
+		}
+		if cb.SlowRate > 1 {
+			return configErrorf(joinKey(key, "circuit_breaker.slow_rate"), "must be between 0 and 1, got %v", cb.SlowRate)
+		}
+	}
+	return nil
+}
+
+// Names 返回排序后的客户端名称
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Clients))
+	for name := range c.Clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewPoolFromConfig 根据配置创建连接池，并发添加配置中的所有客户端，之后可以通过Pool.Reload更新客户端
+// 任一客户端创建失败或注册指标失败时关闭连接池并返回错误
+// ctx: 创建客户端使用的上下文，启用延迟连接时不使用
+// opts: 在配置文件之后应用的连接池选项，用于设置配置文件无法表示的选项，例如WithPoolMetrics、WithClientOptions
+func NewPoolFromConfig(ctx context.Context, cfg *Config, opts ...PoolOption) (Pool, error) {
+	configured := *cfg.Pool
+	all := append([]PoolOption{func(o *PoolOptions) { *o = configured }}, opts...)
+	p, err := newPool(all...)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.Reload(ctx, cfg); err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// LoadPool 读取配置文件并创建连接池
+// path: 配置文件路径，格式见LoadConfig
+// opts: 在配置文件之后应用的连接池选项
+func LoadPool(ctx context.Context, path string, opts ...PoolOption) (Pool, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPoolFromConfig(ctx, cfg, opts...)
+}
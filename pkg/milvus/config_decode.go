@@ -0,0 +1,456 @@
+package milvus
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+)
+
+// ConfigError 配置错误，指明出错的配置键
+type ConfigError struct {
+	Key string // 出错的配置键路径，例如clients.main.timeouts.read
+	Err error
+}
+
+// Error 实现error接口
+func (e *ConfigError) Error() string {
+	return e.Key + ": " + e.Err.Error()
+}
+
+// Unwrap 返回底层错误
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// configErrorf 创建指向key的配置错误
+func configErrorf(key, format string, args ...any) error {
+	return &ConfigError{Key: key, Err: errors.Errorf(format, args...)}
+}
+
+// joinKey 拼接配置键路径
+func joinKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// envPattern 匹配$$、${VAR}与${VAR:-default}
+var envPattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnv 替换字符串中的环境变量引用
+// ${VAR}要求变量已设置；${VAR:-default}在变量未设置或为空时使用默认值；$$表示字面量$
+func expandEnv(key, s string) (string, error) {
+	var err error
+	out := envPattern.ReplaceAllStringFunc(s, func(m string) string {
+		if m == "$$" {
+			return "$"
+		}
+		sub := envPattern.FindStringSubmatch(m)
+		value, ok := os.LookupEnv(sub[1])
+		if sub[2] != "" {
+			if value == "" {
+				return sub[3]
+			}
+			return value
+		}
+		if !ok && err == nil {
+			err = configErrorf(key, "environment variable %s is not set", sub[1])
+		}
+		return value
+	})
+	return out, err
+}
+
+// interpolate 递归替换配置值中所有字符串的环境变量引用
+// 在解析之后替换，环境变量的内容不会影响配置文件的语法
+func interpolate(key string, v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return expandEnv(key, v)
+	case map[string]any:
+		for k, item := range v {
+			expanded, err := interpolate(joinKey(key, k), item)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = expanded
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			expanded, err := interpolate(fmt.Sprintf("%s[%d]", key, i), item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	case []map[string]any:
+		// TOML的表数组
+		for i, item := range v {
+			if _, err := interpolate(fmt.Sprintf("%s[%d]", key, i), item); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+var (
+	durationType       = reflect.TypeOf(time.Duration(0))
+	operationClassType = reflect.TypeOf(client.OperationClass(0))
+	clientPkgPath      = operationClassType.PkgPath()
+	poolPkgPath        = reflect.TypeOf(PoolOptions{}).PkgPath()
+)
+
+// configKey 返回字段对应的配置键，即字段名的蛇形命名，例如DBName为db_name，MaxSearchNQ为max_search_nq
+func configKey(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// configurable 判断类型能否由配置文件表示
+// 函数、接口、非结构体的切片以及其他包的结构体（如*slog.Logger）只能通过代码设置
+func configurable(t reflect.Type) bool {
+	if t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Pointer:
+		return t.Elem().Kind() == reflect.Struct && configurable(t.Elem())
+	case reflect.Struct:
+		return t.PkgPath() == clientPkgPath || t.PkgPath() == poolPkgPath
+	case reflect.Map:
+		return t.Key() == operationClassType && configurable(t.Elem())
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Struct && configurable(t.Elem())
+	default:
+		return false
+	}
+}
+
+// configFields 返回结构体可配置字段的配置键到字段下标的映射
+func configFields(t reflect.Type) map[string]int {
+	fields := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.IsExported() && configurable(f.Type) {
+			fields[configKey(f.Name)] = i
+		}
+	}
+	return fields
+}
+
+// parseOperationClass 解析操作类别名称
+func parseOperationClass(s string) (client.OperationClass, bool) {
+	for _, c := range []client.OperationClass{client.OpRead, client.OpWrite, client.OpDDL} {
+		if c.String() == s {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// decodeValue 将配置值写入dst，未出现在配置中的字段保持原值
+// key: 当前值的配置键路径，用于错误信息
+func decodeValue(dst reflect.Value, src any, key string) error {
+	t := dst.Type()
+	switch {
+	case t == durationType:
+		d, err := toDuration(src)
+		if err != nil {
+			return &ConfigError{Key: key, Err: err}
+		}
+		dst.SetInt(int64(d))
+		return nil
+
+	case t.Kind() == reflect.Pointer:
+		if src == nil {
+			dst.SetZero()
+			return nil
+		}
+		// 在副本上修改，不影响其他配置共享的值
+		elem := reflect.New(t.Elem())
+		if !dst.IsNil() {
+			elem.Elem().Set(dst.Elem())
+		}
+		if err := decodeValue(elem.Elem(), src, key); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+
+	case t.Kind() == reflect.Struct:
+		m, ok := src.(map[string]any)
+		if !ok {
+			return configErrorf(key, "expected a table, got %s", describe(src))
+		}
+		fields := configFields(t)
+		for _, k := range sortedKeys(m) {
+			i, ok := fields[k]
+			if !ok {
+				return configErrorf(joinKey(key, k), "unknown key")
+			}
+			if err := decodeValue(dst.Field(i), m[k], joinKey(key, k)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case t.Kind() == reflect.Map:
+		if src == nil {
+			dst.SetZero()
+			return nil
+		}
+		m, ok := src.(map[string]any)
+		if !ok {
+			return configErrorf(key, "expected a table, got %s", describe(src))
+		}
+		merged := reflect.MakeMap(t)
+		if !dst.IsNil() {
+			iter := dst.MapRange()
+			for iter.Next() {
+				merged.SetMapIndex(iter.Key(), iter.Value())
+			}
+		}
+		for _, k := range sortedKeys(m) {
+			class, ok := parseOperationClass(k)
+			if !ok {
+				return configErrorf(joinKey(key, k), "unknown operation class, expected read, write or ddl")
+			}
+			elem := reflect.New(t.Elem()).Elem()
+			if existing := merged.MapIndex(reflect.ValueOf(class)); existing.IsValid() {
+				elem.Set(existing)
+			}
+			if err := decodeValue(elem, m[k], joinKey(key, k)); err != nil {
+				return err
+			}
+			if elem.Kind() == reflect.Pointer && elem.IsNil() {
+				merged.SetMapIndex(reflect.ValueOf(class), reflect.Value{})
+				continue
+			}
+			merged.SetMapIndex(reflect.ValueOf(class), elem)
+		}
+		dst.Set(merged)
+		return nil
+
+	case t.Kind() == reflect.Slice:
+		// 列表整体替换原值，不与默认值合并
+		var items []any
+		switch v := src.(type) {
+		case nil:
+			dst.SetZero()
+			return nil
+		case []any:
+			items = v
+		case []map[string]any:
+			for _, item := range v {
+				items = append(items, item)
+			}
+		default:
+			return configErrorf(key, "expected a list, got %s", describe(src))
+		}
+		list := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(list.Index(i), item, fmt.Sprintf("%s[%d]", key, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(list)
+		return nil
+	}
+
+	if err := decodeScalar(dst, src); err != nil {
+		return &ConfigError{Key: key, Err: err}
+	}
+	return nil
+}
+
+// decodeScalar 写入布尔、数字与字符串，字符串形式的布尔值与数字（通常来自环境变量）会被解析
+func decodeScalar(dst reflect.Value, src any) error {
+	switch dst.Kind() {
+	case reflect.String:
+		switch v := src.(type) {
+		case string:
+			dst.SetString(v)
+		case bool, int, int64, uint64, json.Number:
+			dst.SetString(fmt.Sprint(v))
+		default:
+			return errors.Errorf("expected a string, got %s", describe(src))
+		}
+		return nil
+
+	case reflect.Bool:
+		switch v := src.(type) {
+		case bool:
+			dst.SetBool(v)
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return errors.Errorf("invalid boolean %q", v)
+			}
+			dst.SetBool(b)
+		default:
+			return errors.Errorf("expected a boolean, got %s", describe(src))
+		}
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := toInt(src)
+		if err != nil {
+			return err
+		}
+		if n < 0 {
+			return errors.Errorf("must not be negative, got %d", n)
+		}
+		if dst.CanInt() && dst.OverflowInt(n) || dst.CanUint() && dst.OverflowUint(uint64(n)) {
+			return errors.Errorf("value %d is out of range", n)
+		}
+		if dst.CanInt() {
+			dst.SetInt(n)
+		} else {
+			dst.SetUint(uint64(n))
+		}
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat(src)
+		if err != nil {
+			return err
+		}
+		if f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+			return errors.Errorf("must be a non-negative finite number, got %v", f)
+		}
+		dst.SetFloat(f)
+		return nil
+	}
+	return errors.Errorf("unsupported type %s", dst.Type())
+}
+
+// toInt 将配置值转换为整数
+func toInt(src any) (int64, error) {
+	switch v := src.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, errors.Errorf("value %d is out of range", v)
+		}
+		return int64(v), nil
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt64 {
+			return 0, errors.Errorf("expected an integer, got %v", v)
+		}
+		return int64(v), nil
+	case json.Number:
+		return toInt(string(v))
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return 0, errors.Errorf("invalid integer %q", v)
+		}
+		return n, nil
+	}
+	return 0, errors.Errorf("expected an integer, got %s", describe(src))
+}
+
+// toFloat 将配置值转换为浮点数
+func toFloat(src any) (float64, error) {
+	switch v := src.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case json.Number:
+		return toFloat(string(v))
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, errors.Errorf("invalid number %q", v)
+		}
+		return f, nil
+	}
+	return 0, errors.Errorf("expected a number, got %s", describe(src))
+}
+
+// toDuration 将配置值转换为时间间隔，只接受"5s"、"1m30s"这样的字符串与0
+func toDuration(src any) (time.Duration, error) {
+	if s, ok := src.(string); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return 0, errors.Errorf("invalid duration %q", s)
+		}
+		if d < 0 {
+			return 0, errors.Errorf("must not be negative, got %s", s)
+		}
+		return d, nil
+	}
+	if n, err := toInt(src); err == nil && n == 0 {
+		return 0, nil
+	}
+	return 0, errors.Errorf(`expected a duration string like "5s", got %s`, describe(src))
+}
+
+// describe 返回配置值的简要描述，用于错误信息
+func describe(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "a table"
+	case []any:
+		return "a list"
+	case string:
+		return strconv.Quote(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// sortedKeys 返回排序后的键，保证错误信息稳定
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,289 @@
+package milvus
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+const yamlConfig = `
+pool:
+  lazy_connect: true
+  connect_timeout: 3s
+clients:
+  main:
+    address: ${MILVUS_TEST_HOST}:19530
+    username: root
+    password: ${MILVUS_TEST_PASSWORD}
+    db_name: ${MILVUS_TEST_DB:-default_db}
+    keepalive_time: 10s
+    max_search_nq: 1024
+    timeouts:
+      read: 5s
+    retry_policies:
+      read:
+        max_attempts: 5
+      ddl: null
+    rate_limits:
+      write:
+        rows_per_second: 10000
+        max_in_flight: 4
+    circuit_breaker:
+      error_rate: 0.5
+      open_timeout: 30s
+  analytics:
+    address: analytics:19530
+    max_retry: "10"
+    tls:
+      ca_file: /etc/milvus/ca.pem
+      min_version: "1.3"
+      reload_interval: 1m
+    endpoints:
+      - address: analytics-a:19530
+      - address: ${MILVUS_TEST_HOST}:19530
+        priority: 1
+    failover:
+      failure_threshold: 2
+      failback_delay: 1m
+`
+
+const jsonConfig = `{
+  "pool": {"lazy_connect": true, "connect_timeout": "3s"},
+  "clients": {
+    "main": {
+      "address": "${MILVUS_TEST_HOST}:19530",
+      "username": "root",
+      "password": "${MILVUS_TEST_PASSWORD}",
+      "db_name": "${MILVUS_TEST_DB:-default_db}",
+      "keepalive_time": "10s",
+      "max_search_nq": 1024,
+      "timeouts": {"read": "5s"},
+      "retry_policies": {"read": {"max_attempts": 5}, "ddl": null},
+      "rate_limits": {"write": {"rows_per_second": 10000, "max_in_flight": 4}},
+      "circuit_breaker": {"error_rate": 0.5, "open_timeout": "30s"}
+    },
+    "analytics": {
+      "address": "analytics:19530",
+      "max_retry": "10",
+      "tls": {"ca_file": "/etc/milvus/ca.pem", "min_version": "1.3", "reload_interval": "1m"},
+      "endpoints": [{"address": "analytics-a:19530"}, {"address": "${MILVUS_TEST_HOST}:19530", "priority": 1}],
+      "failover": {"failure_threshold": 2, "failback_delay": "1m"}
+    }
+  }
+}`
+
+const tomlConfig = `
+[pool]
+lazy_connect = true
+connect_timeout = "3s"
+
+[clients.main]
+address = "${MILVUS_TEST_HOST}:19530"
+username = "root"
+password = "${MILVUS_TEST_PASSWORD}"
+db_name = "${MILVUS_TEST_DB:-default_db}"
+keepalive_time = "10s"
+max_search_nq = 1024
+timeouts = { read = "5s" }
+retry_policies = { read = { max_attempts = 5 } }
+rate_limits = { write = { rows_per_second = 10000, max_in_flight = 4 } }
+circuit_breaker = { error_rate = 0.5, open_timeout = "30s" }
+
+[clients.analytics]
+address = "analytics:19530"
+max_retry = "10"
+tls = { ca_file = "/etc/milvus/ca.pem", min_version = "1.3", reload_interval = "1m" }
+failover = { failure_threshold = 2, failback_delay = "1m" }
+
+[[clients.analytics.endpoints]]
+address = "analytics-a:19530"
+
+[[clients.analytics.endpoints]]
+address = "${MILVUS_TEST_HOST}:19530"
+priority = 1
+`
+
+// TestParseConfig 测试解析配置文件
+func TestParseConfig(t *testing.T) {
+	t.Setenv("MILVUS_TEST_HOST", "milvus")
+	t.Setenv("MILVUS_TEST_PASSWORD", "p@ss$word")
+
+	for format, data := range map[string]string{"yaml": yamlConfig, "json": jsonConfig, "toml": tomlConfig} {
+		t.Run(format+"格式", func(t *testing.T) {
+			cfg, err := ParseConfig([]byte(data), format)
+			require.NoError(t, err)
+
+			assert.True(t, cfg.Pool.LazyConnect)
+			assert.Equal(t, 3*time.Second, cfg.Pool.ConnectTimeout)
+			assert.Equal(t, DefaultPoolOptions().HealthCheckTimeout, cfg.Pool.HealthCheckTimeout, "未配置的字段保持默认值")
+			assert.Equal(t, []string{"analytics", "main"}, cfg.Names())
+
+			o := cfg.Clients["main"].Options
+			assert.Equal(t, "milvus:19530", o.Address)
+			assert.Equal(t, "root", o.Username)
+			assert.Equal(t, "p@ss$word", o.Password, "环境变量的值不再替换")
+			assert.Equal(t, "default_db", o.DBName)
+			assert.Equal(t, 10*time.Second, o.KeepaliveTime)
+			assert.Equal(t, 1024, o.MaxSearchNQ)
+			assert.Equal(t, 5*time.Second, o.Timeouts[client.OpRead])
+			assert.Equal(t, client.DefaultTimeouts()[client.OpWrite], o.Timeouts[client.OpWrite])
+			assert.Equal(t, 5, o.RetryPolicies[client.OpRead].MaxAttempts)
+			assert.Equal(t, client.DefaultRetryPolicies()[client.OpRead].InitialBackoff, o.RetryPolicies[client.OpRead].InitialBackoff)
+			assert.Equal(t, &client.RateLimit{RowsPerSecond: 10000, MaxInFlight: 4}, o.RateLimits[client.OpWrite])
+			require.NotNil(t, o.CircuitBreaker)
+			assert.Equal(t, 0.5, o.CircuitBreaker.ErrorRate)
+			assert.Equal(t, 30*time.Second, o.CircuitBreaker.OpenTimeout)
+			if format != "toml" {
+				assert.NotContains(t, o.RetryPolicies, client.OpDDL, "null删除默认值")
+			}
+
+			assert.Equal(t, uint(10), cfg.Clients["analytics"].Options.MaxRetry, "字符串形式的数字")
+			assert.Equal(t, &client.TLSConfig{CAFile: "/etc/milvus/ca.pem", MinVersion: "1.3", ReloadInterval: time.Minute},
+				cfg.Clients["analytics"].Options.TLS)
+			assert.Equal(t, []client.Endpoint{{Address: "analytics-a:19530"}, {Address: "milvus:19530", Priority: 1}},
+				cfg.Clients["analytics"].Options.Endpoints, "表数组中的环境变量")
+			failover := cfg.Clients["analytics"].Options.Failover
+			require.NotNil(t, failover)
+			assert.Equal(t, 2, failover.FailureThreshold)
+			assert.Equal(t, time.Minute, failover.FailbackDelay)
+		})
+	}
+
+	t.Run("环境变量默认值与转义", func(t *testing.T) {
+		t.Setenv("MILVUS_TEST_DB", "prod")
+		cfg, err := ParseConfig([]byte("clients:\n  main:\n    db_name: ${MILVUS_TEST_DB:-default_db}\n    password: $$ecret\n"), "yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "prod", cfg.Clients["main"].Options.DBName)
+		assert.Equal(t, "$ecret", cfg.Clients["main"].Options.Password)
+	})
+
+	t.Run("选项只覆盖配置的字段", func(t *testing.T) {
+		cfg, err := ParseConfig([]byte("clients:\n  main:\n    address: milvus:19530\n"), "yaml")
+		require.NoError(t, err)
+
+		o := client.DefaultOptions()
+		client.WithSlowThreshold(5 * time.Second)(o)
+		cfg.Clients["main"].Option()(o)
+		assert.Equal(t, "milvus:19530", o.Address)
+		assert.Equal(t, 5*time.Second, o.SlowThreshold)
+	})
+}
+
+// TestParseConfigErrors 测试配置错误指明出错的配置键
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		key     string
+		message string
+	}{
+		{"未知的键", "clients:\n  main:\n    adress: milvus:19530\n", "clients.main.adress", "unknown key"},
+		{"未知的顶层键", "client:\n  main: {}\n", "client", "unknown key"},
+		{"无法配置的字段", "clients:\n  main:\n    logger: stdout\n", "clients.main.logger", "unknown key"},
+		{"无效的时间间隔", "clients:\n  main:\n    timeouts:\n      read: 5 seconds\n", "clients.main.timeouts.read", "invalid duration"},
+		{"时间间隔缺少单位", "pool:\n  connect_timeout: 5\n", "pool.connect_timeout", "duration string"},
+		{"未知的操作类别", "clients:\n  main:\n    timeouts:\n      search: 5s\n", "clients.main.timeouts.search", "unknown operation class"},
+		{"类型错误", "clients:\n  main:\n    max_retry: many\n", "clients.main.max_retry", "invalid integer"},
+		{"负数", "clients:\n  main:\n    max_search_nq: -1\n", "clients.main.max_search_nq", "must not be negative"},
+		{"超出取值范围", "clients:\n  main:\n    circuit_breaker:\n      error_rate: 1.5\n", "clients.main.circuit_breaker.error_rate", "between 0 and 1"},
+		{"地址为空", "clients:\n  main:\n    address: ''\n", "clients.main.address", "must not be empty"},
+		{"客户端不是表", "clients:\n  main: milvus:19530\n", "clients.main", "expected a table"},
+		{"环境变量未设置", "clients:\n  main:\n    password: ${MILVUS_TEST_UNSET}\n", "clients.main.password", "MILVUS_TEST_UNSET is not set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.config), "yaml")
+			require.Error(t, err)
+			var cfgErr *ConfigError
+			require.ErrorAs(t, err, &cfgErr)
+			assert.Equal(t, tt.key, cfgErr.Key)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+
+	t.Run("语法错误", func(t *testing.T) {
+		_, err := ParseConfig([]byte(`{"clients": `), "json")
+		assert.ErrorContains(t, err, "failed to parse json config")
+	})
+
+	t.Run("不支持的格式", func(t *testing.T) {
+		_, err := ParseConfig([]byte(""), "ini")
+		assert.ErrorContains(t, err, "unsupported config format")
+	})
+}
+
+// TestConfigKey 测试字段名到配置键的转换
+func TestConfigKey(t *testing.T) {
+	for name, key := range map[string]string{
+		"Address":                "address",
+		"DBName":                 "db_name",
+		"APIKey":                 "api_key",
+		"EnableTLSAuth":          "enable_tls_auth",
+		"MaxSearchNQ":            "max_search_nq",
+		"MaxRecvMsgSize":         "max_recv_msg_size",
+		"AllowAutoIDInsertRetry": "allow_auto_id_insert_retry",
+	} {
+		assert.Equal(t, key, configKey(name), name)
+	}
+}
+
+// TestLoadPool 测试根据配置文件创建连接池
+func TestLoadPool(t *testing.T) {
+	addr := testserver.Start(t)
+	t.Setenv("MILVUS_TEST_ADDRESS", addr)
+
+	path := filepath.Join(t.TempDir(), "milvus.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+clients:
+  main:
+    address: ${MILVUS_TEST_ADDRESS}
+    disable_conn: true
+    db_name: db1
+  backup:
+    address: ${MILVUS_TEST_ADDRESS}
+    disable_conn: true
+`), 0o644))
+
+	t.Run("添加配置中的所有客户端", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		pl, err := LoadPool(ctx, path, WithClientOptions(client.WithSlowThreshold(5*time.Second)))
+		require.NoError(t, err)
+		defer pl.Close()
+
+		assert.ElementsMatch(t, []string{"main", "backup"}, pl.List())
+		cfg, err := pl.Config("main")
+		require.NoError(t, err)
+		assert.Equal(t, addr, cfg.Address)
+		assert.Equal(t, "db1", cfg.DBName)
+		assert.Equal(t, 5*time.Second, cfg.SlowThreshold, "共用选项")
+
+		_, err = pl.Get("backup")
+		assert.NoError(t, err)
+	})
+
+	t.Run("客户端创建失败时指明客户端", func(t *testing.T) {
+		cfg, err := ParseConfig([]byte("clients:\n  main:\n    address: "+addr+"\n    disable_conn: true\n  bad:\n    address: 127.0.0.1:1\n"), "yaml")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		_, err = NewPoolFromConfig(ctx, cfg)
+		var cfgErr *ConfigError
+		require.ErrorAs(t, err, &cfgErr)
+		assert.Equal(t, "clients.bad", cfgErr.Key)
+	})
+
+	t.Run("文件不存在", func(t *testing.T) {
+		_, err := LoadPool(context.Background(), filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
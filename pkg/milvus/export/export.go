@@ -0,0 +1,234 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/pkg/errors"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+)
+
+// Options 定义导出器的配置选项
+type Options struct {
+	BatchSize      int      // 每次分页查询的行数，决定导出时的内存占用上限，默认1000
+	PartitionNames []string // 要导出的分区名称列表，空表示导出所有分区
+	Filter         string   // 过滤条件表达式，空表示导出全部数据，例如"id > 0"
+	OutputFields   []string // 要导出的字段名称列表，空表示导出全部字段；主键字段总会被导出
+}
+
+// DefaultOptions 返回默认配置
+func DefaultOptions() *Options {
+	return &Options{
+		BatchSize: 1000,
+	}
+}
+
+// Option 定义配置选项函数类型
+type Option func(*Options)
+
+// WithBatchSize 设置每次分页查询的行数
+func WithBatchSize(batchSize int) Option {
+	return func(o *Options) {
+		o.BatchSize = batchSize
+	}
+}
+
+// WithPartitions 设置要导出的分区
+func WithPartitions(partitionNames ...string) Option {
+	return func(o *Options) {
+		o.PartitionNames = partitionNames
+	}
+}
+
+// WithFilter 设置过滤条件表达式
+func WithFilter(expr string) Option {
+	return func(o *Options) {
+		o.Filter = expr
+	}
+}
+
+// WithOutputFields 设置要导出的字段
+func WithOutputFields(fields ...string) Option {
+	return func(o *Options) {
+		o.OutputFields = fields
+	}
+}
+
+// Exporter 集合导出器，通过按主键游标分页查询流式导出集合数据
+type Exporter struct {
+	cli     client.Client
+	options *Options
+}
+
+// NewExporter 创建导出器
+// cli: Milvus客户端
+// opts: 配置选项列表
+func NewExporter(cli client.Client, opts ...Option) *Exporter {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultOptions().BatchSize
+	}
+	return &Exporter{cli: cli, options: options}
+}
+
+// Header 返回集合的模式头，字段范围受OutputFields选项影响
+// ctx: 上下文，用于控制请求生命周期
+// collectionName: 集合名称，例如"my_collection"
+func (e *Exporter) Header(ctx context.Context, collectionName string) (*SchemaHeader, error) {
+	coll, err := e.cli.DescribeCollection(ctx, collectionName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe collection")
+	}
+	header := NewSchemaHeader(coll.Schema, e.options.OutputFields)
+	if header.Collection == "" {
+		header.Collection = collectionName
+	}
+	if len(e.options.PartitionNames) == 1 {
+		header.Partition = e.options.PartitionNames[0]
+	}
+	if header.PrimaryField() == nil {
+		return nil, fmt.Errorf("collection %s has no primary key field", collectionName)
+	}
+	return header, nil
+}
+
+// Export 将集合数据流式写入写入器，写入完成后关闭写入器
+// ctx: 上下文，用于控制请求生命周期
+// collectionName: 集合名称，例如"my_collection"
+// w: 导出写入器，例如NewJSONLWriter(file)
+// 返回值: (导出的行数, 错误信息)
+func (e *Exporter) Export(ctx context.Context, collectionName string, w Writer) (int64, error) {
+	header, err := e.Header(ctx, collectionName)
+	if err != nil {
+		return 0, err
+	}
+	if err := w.WriteHeader(header); err != nil {
+		return 0, errors.Wrap(err, "failed to write schema header")
+	}
+
+	total, err := e.Scan(ctx, header, func(columns []column.Column) error {
+		return w.WriteRows(columns)
+	})
+	if err != nil {
+		w.Close()
+		return total, err
+	}
+	if err := w.Close(); err != nil {
+		return total, errors.Wrap(err, "failed to close writer")
+	}
+	return total, nil
+}
+
+// ExportFile 将集合数据导出到文件
+// ctx: 上下文，用于控制请求生命周期
+// collectionName: 集合名称，例如"my_collection"
+// format: 导出格式，例如FormatParquet
+// path: 文件路径，已存在时会被覆盖，例如"/tmp/my_collection.parquet"
+// 返回值: (导出的行数, 错误信息)
+func (e *Exporter) ExportFile(ctx context.Context, collectionName string, format Format, path string) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create export file")
+	}
+	defer f.Close()
+
+	w, err := NewWriter(format, f)
+	if err != nil {
+		return 0, err
+	}
+	total, err := e.Export(ctx, collectionName, w)
+	if err != nil {
+		return total, err
+	}
+	return total, f.Sync()
+}
+
+// Scan 按主键游标分页遍历集合数据，每页回调一次
+// 每页最多BatchSize行，内存占用与集合大小无关；依赖Milvus对带limit的查询按主键升序归并结果
+// ctx: 上下文，用于控制请求生命周期
+// header: 模式头，决定查询的集合与输出字段
+// fn: 每页数据的回调，返回错误时终止遍历
+// 返回值: (遍历的行数, 错误信息)
+func (e *Exporter) Scan(ctx context.Context, header *SchemaHeader, fn func(columns []column.Column) error) (int64, error) {
+	pk := header.PrimaryField()
+	if pk == nil {
+		return 0, fmt.Errorf("collection %s has no primary key field", header.Collection)
+	}
+
+	var (
+		total  int64
+		cursor string
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		columns, err := e.cli.QueryWithLimit(ctx, header.Collection, e.options.PartitionNames,
+			combineExpr(e.options.Filter, cursor), header.FieldNames(), e.options.BatchSize)
+		if err != nil {
+			return total, errors.Wrap(err, "failed to query collection")
+		}
+
+		n := rowCount(columns)
+		if n == 0 {
+			return total, nil
+		}
+		if err := fn(columns); err != nil {
+			return total, err
+		}
+		total += int64(n)
+		if n < e.options.BatchSize {
+			return total, nil
+		}
+
+		if cursor, err = cursorExpr(pk, columns, n-1); err != nil {
+			return total, err
+		}
+	}
+}
+
+// combineExpr 组合用户过滤条件与主键游标条件
+func combineExpr(filter, cursor string) string {
+	switch {
+	case filter == "":
+		return cursor
+	case cursor == "":
+		return filter
+	default:
+		return fmt.Sprintf("(%s) && (%s)", filter, cursor)
+	}
+}
+
+// cursorExpr 根据当前页最后一行的主键生成下一页的游标条件
+func cursorExpr(pk *FieldHeader, columns []column.Column, row int) (string, error) {
+	for _, col := range columns {
+		if col.Name() != pk.Name {
+			continue
+		}
+		switch col.Type() {
+		case entity.FieldTypeInt64:
+			id, err := col.GetAsInt64(row)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s > %d", pk.Name, id), nil
+		case entity.FieldTypeVarChar, entity.FieldTypeString:
+			id, err := col.GetAsString(row)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s > %s", pk.Name, quoteString(id)), nil
+		default:
+			return "", fmt.Errorf("unsupported primary key type %s", FieldTypeName(col.Type()))
+		}
+	}
+	return "", fmt.Errorf("primary key field %s missing from query result", pk.Name)
+}
@@ -0,0 +1,313 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+)
+
+const (
+	// 测试配置
+	testAddress  = "192.168.103.113:19530"
+	testUsername = "root"
+	testPassword = ""
+)
+
+func createTestSchema(collectionName string) *entity.Schema {
+	return &entity.Schema{
+		CollectionName:     collectionName,
+		Description:        "Test collection for export",
+		EnableDynamicField: false,
+		Fields: []*entity.Field{
+			{Name: "id", DataType: entity.FieldTypeInt64, PrimaryKey: true},
+			{Name: "vector", DataType: entity.FieldTypeFloatVector, TypeParams: map[string]string{"dim": "4"}},
+			{Name: "text", DataType: entity.FieldTypeVarChar, TypeParams: map[string]string{"max_length": "100"}},
+			{Name: "meta", DataType: entity.FieldTypeJSON},
+			{Name: "tags", DataType: entity.FieldTypeArray, ElementType: entity.FieldTypeInt16, TypeParams: map[string]string{"max_capacity": "8"}},
+		},
+	}
+}
+
+func createTestColumns() []column.Column {
+	return []column.Column{
+		column.NewColumnInt64("id", []int64{1, 2}),
+		column.NewColumnFloatVector("vector", 4, [][]float32{{0.1, 0.2, 0.3, 0.4}, {1, 2, 3, 4}}),
+		column.NewColumnVarChar("text", []string{"hello", "wor,ld"}),
+		column.NewColumnJSONBytes("meta", [][]byte{[]byte(`{"a":1}`), []byte(`{"b":"x"}`)}),
+		column.NewColumnInt16Array("tags", [][]int16{{1, 2}, {}}),
+	}
+}
+
+// TestSchemaHeader 测试模式头的构建与还原
+func TestSchemaHeader(t *testing.T) {
+	schema := createTestSchema("export_schema")
+	schema.EnableDynamicField = true
+
+	t.Run("构建模式头", func(t *testing.T) {
+		header := NewSchemaHeader(schema, nil)
+		assert.Equal(t, []string{"id", "vector", "text", "meta", "tags", DynamicFieldName}, header.FieldNames())
+		assert.Equal(t, "id", header.PrimaryField().Name)
+		assert.Equal(t, "Int16", header.Fields[4].ElementType)
+	})
+
+	t.Run("指定输出字段时保留主键", func(t *testing.T) {
+		header := NewSchemaHeader(schema, []string{"text"})
+		assert.Equal(t, []string{"id", "text"}, header.FieldNames())
+	})
+
+	t.Run("还原集合模式", func(t *testing.T) {
+		restored, err := NewSchemaHeader(schema, nil).Schema()
+		require.NoError(t, err)
+		assert.Equal(t, schema.CollectionName, restored.CollectionName)
+		assert.True(t, restored.EnableDynamicField)
+		require.Len(t, restored.Fields, len(schema.Fields))
+		for i, f := range schema.Fields {
+			assert.Equal(t, f.Name, restored.Fields[i].Name)
+			assert.Equal(t, f.DataType, restored.Fields[i].DataType)
+		}
+	})
+
+	t.Run("经JSON还原字段默认值", func(t *testing.T) {
+		withDefaults := entity.NewSchema().WithName("defaults").
+			WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+			WithField(entity.NewField().WithName("score").WithDataType(entity.FieldTypeDouble).WithDefaultValueDouble(1.5)).
+			WithField(entity.NewField().WithName("level").WithDataType(entity.FieldTypeInt32).WithDefaultValueInt(3)).
+			WithField(entity.NewField().WithName("status").WithDataType(entity.FieldTypeVarChar).WithMaxLength(16).WithDefaultValueString("new")).
+			WithField(entity.NewField().WithName("active").WithDataType(entity.FieldTypeBool).WithDefaultValueBool(true))
+
+		data, err := json.Marshal(NewSchemaHeader(withDefaults, nil))
+		require.NoError(t, err)
+		var header SchemaHeader
+		require.NoError(t, json.Unmarshal(data, &header))
+		restored, err := header.Schema()
+		require.NoError(t, err)
+		for i, f := range withDefaults.Fields {
+			assert.True(t, proto.Equal(f.DefaultValue, restored.Fields[i].DefaultValue), f.Name)
+		}
+
+		header.Fields[1].DefaultValue = "high"
+		_, err = header.Schema()
+		assert.ErrorContains(t, err, "field score default value: expected number")
+	})
+
+	t.Run("解析未知类型失败", func(t *testing.T) {
+		_, err := ParseFieldType("Unknown")
+		assert.Error(t, err)
+	})
+}
+
+// TestJSONLWriter 测试JSON Lines写入器
+func TestJSONLWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONLWriter(&buf)
+	require.NoError(t, w.WriteHeader(NewSchemaHeader(createTestSchema("export_jsonl"), nil)))
+	require.NoError(t, w.WriteRows(createTestColumns()))
+	require.NoError(t, w.Close())
+
+	scanner := bufio.NewScanner(&buf)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.Len(t, lines, 3)
+
+	var header map[string]SchemaHeader
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+	assert.Equal(t, "export_jsonl", header[SchemaHeaderKey].Collection)
+
+	var row map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &row))
+	assert.Equal(t, float64(2), row["id"])
+	assert.Equal(t, []any{float64(1), float64(2), float64(3), float64(4)}, row["vector"])
+	assert.Equal(t, map[string]any{"b": "x"}, row["meta"])
+	assert.Equal(t, []any{}, row["tags"])
+}
+
+// TestCSVWriter 测试CSV写入器
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf)
+	require.NoError(t, w.WriteHeader(NewSchemaHeader(createTestSchema("export_csv"), nil)))
+	require.NoError(t, w.WriteRows(createTestColumns()))
+	require.NoError(t, w.Close())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"id", "vector", "text", "meta", "tags"}, records[0])
+	assert.Equal(t, []string{"1", "[0.1,0.2,0.3,0.4]", "hello", `{"a":1}`, "[1,2]"}, records[1])
+	assert.Equal(t, "wor,ld", records[2][2])
+}
+
+// TestParquetWriter 测试Parquet写入器
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf)
+	require.NoError(t, w.WriteHeader(NewSchemaHeader(createTestSchema("export_parquet"), nil)))
+	require.NoError(t, w.WriteRows(createTestColumns()))
+	require.NoError(t, w.Close())
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), f.NumRows())
+
+	meta, ok := f.Lookup(SchemaHeaderKey)
+	require.True(t, ok)
+	var header SchemaHeader
+	require.NoError(t, json.Unmarshal([]byte(meta), &header))
+	assert.Equal(t, "export_parquet", header.Collection)
+
+	r := parquet.NewReader(bytes.NewReader(buf.Bytes()))
+	row := map[string]any{}
+	require.NoError(t, r.Read(&row))
+	assert.Equal(t, int64(1), row["id"])
+	assert.Equal(t, "hello", row["text"])
+	assert.Len(t, row["vector"], 4)
+}
+
+// TestJSONLReader 测试JSON Lines读取器还原列数据
+func TestJSONLReader(t *testing.T) {
+	schema := createTestSchema("export_reader")
+	schema.EnableDynamicField = true
+	schema.Fields = append(schema.Fields,
+		&entity.Field{Name: "score", DataType: entity.FieldTypeDouble, Nullable: true},
+		&entity.Field{Name: "bin", DataType: entity.FieldTypeBinaryVector, TypeParams: map[string]string{"dim": "16"}},
+	)
+	score, err := column.NewNullableColumnDouble("score", []float64{0.5}, []bool{true, false})
+	require.NoError(t, err)
+	columns := append(createTestColumns(),
+		score,
+		column.NewColumnBinaryVector("bin", 16, [][]byte{{1, 2}, {3, 4}}),
+		column.NewColumnJSONBytes(DynamicFieldName, [][]byte{[]byte(`{"k":1}`), []byte(`{}`)}),
+	)
+
+	var buf bytes.Buffer
+	w := NewJSONLWriter(&buf)
+	require.NoError(t, w.WriteHeader(NewSchemaHeader(schema, nil)))
+	require.NoError(t, w.WriteRows(columns))
+	require.NoError(t, w.Close())
+
+	r, err := NewJSONLReader(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "export_reader", r.Header().Collection)
+
+	got, err := r.Read(1)
+	require.NoError(t, err)
+	require.Len(t, got, 8)
+	assert.Equal(t, 1, got[0].Len())
+
+	got, err = r.Read(10)
+	require.NoError(t, err)
+	id, err := got[0].GetAsInt64(0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), id)
+	vector, err := got[1].Get(0)
+	require.NoError(t, err)
+	assert.Equal(t, entity.FloatVector{1, 2, 3, 4}, vector)
+	isNull, err := got[5].IsNull(0)
+	require.NoError(t, err)
+	assert.True(t, isNull)
+	bin, err := got[6].Get(0)
+	require.NoError(t, err)
+	assert.Equal(t, entity.BinaryVector{3, 4}, bin)
+
+	_, err = r.Read(10)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+// TestNewWriter 测试按格式创建写入器
+func TestNewWriter(t *testing.T) {
+	for _, format := range []Format{FormatJSONL, FormatCSV, FormatParquet} {
+		w, err := NewWriter(format, &bytes.Buffer{})
+		assert.NoError(t, err)
+		assert.NotNil(t, w)
+	}
+	_, err := NewWriter("xml", &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+// TestCombineExpr 测试过滤条件与游标条件的组合
+func TestCombineExpr(t *testing.T) {
+	assert.Equal(t, "", combineExpr("", ""))
+	assert.Equal(t, "id > 10", combineExpr("", "id > 10"))
+	assert.Equal(t, "text != \"\"", combineExpr("text != \"\"", ""))
+	assert.Equal(t, "(a > 1) && (id > 10)", combineExpr("a > 1", "id > 10"))
+
+	cursor, err := cursorExpr(&FieldHeader{Name: "pk"}, []column.Column{
+		column.NewColumnVarChar("pk", []string{"a", `b"c`}),
+	}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, `pk > "b\"c"`, cursor)
+}
+
+// TestExport 测试从Milvus服务器导出集合
+func TestExport(t *testing.T) {
+	connectCtx, connectCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer connectCancel()
+
+	cli, err := client.New(connectCtx, testAddress, testUsername, testPassword)
+	if err != nil {
+		t.Skipf("跳过测试，无法连接到Milvus服务器: %v", err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	collectionName := fmt.Sprintf("test_export_%d_%d", time.Now().Unix(), rand.Intn(10000))
+	require.NoError(t, cli.CreateCollection(ctx, createTestSchema(collectionName), 1))
+	defer cli.DropCollection(ctx, collectionName)
+
+	const total = 25
+	ids := make([]int64, total)
+	vectors := make([][]float32, total)
+	texts := make([]string, total)
+	metas := make([][]byte, total)
+	tags := make([][]int16, total)
+	for i := 0; i < total; i++ {
+		ids[i] = int64(i + 1)
+		vectors[i] = []float32{rand.Float32(), rand.Float32(), rand.Float32(), rand.Float32()}
+		texts[i] = fmt.Sprintf("text_%d", i)
+		metas[i] = []byte(fmt.Sprintf(`{"i":%d}`, i))
+		tags[i] = []int16{int16(i)}
+	}
+	_, err = cli.Insert(ctx, collectionName, "",
+		column.NewColumnInt64("id", ids),
+		column.NewColumnFloatVector("vector", 4, vectors),
+		column.NewColumnVarChar("text", texts),
+		column.NewColumnJSONBytes("meta", metas),
+		column.NewColumnInt16Array("tags", tags),
+	)
+	require.NoError(t, err)
+	require.NoError(t, cli.CreateIndex(ctx, collectionName, "vector", index.NewFlatIndex(entity.L2)))
+	require.NoError(t, cli.LoadCollection(ctx, collectionName))
+
+	exporter := NewExporter(cli, WithBatchSize(10))
+	dir := t.TempDir()
+
+	for _, format := range []Format{FormatJSONL, FormatCSV, FormatParquet} {
+		t.Run(fmt.Sprintf("导出%s", format), func(t *testing.T) {
+			n, err := exporter.ExportFile(ctx, collectionName, format, filepath.Join(dir, collectionName+"."+string(format)))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(total), n)
+		})
+	}
+}
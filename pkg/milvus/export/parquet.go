@@ -0,0 +1,187 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/parquet-go/parquet-go"
+	"github.com/pkg/errors"
+)
+
+// parquetWriter Parquet 写入器
+type parquetWriter struct {
+	out    io.Writer
+	w      *parquet.Writer
+	header *SchemaHeader
+	types  []entity.FieldType
+}
+
+// NewParquetWriter 创建Parquet写入器
+// 模式头以JSON形式写入文件元数据（键为SchemaHeaderKey）；可空字段与动态字段为optional列，
+// 浮点向量与数组为LIST列，二进制类向量为BYTE_ARRAY列，JSON与稀疏向量为JSON列
+func NewParquetWriter(w io.Writer) Writer {
+	return &parquetWriter{out: w}
+}
+
+// WriteHeader 根据模式头构建Parquet模式
+func (p *parquetWriter) WriteHeader(header *SchemaHeader) error {
+	group := parquet.Group{}
+	p.types = make([]entity.FieldType, len(header.Fields))
+	for i, f := range header.Fields {
+		dataType, err := ParseFieldType(f.DataType)
+		if err != nil {
+			return errors.Wrapf(err, "field %s", f.Name)
+		}
+		node, err := parquetNode(dataType, f.ElementType)
+		if err != nil {
+			return errors.Wrapf(err, "field %s", f.Name)
+		}
+		if f.Nullable || f.IsDynamic {
+			node = parquet.Optional(node)
+		}
+		group[f.Name] = node
+		p.types[i] = dataType
+	}
+
+	meta, err := json.Marshal(header)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode schema header")
+	}
+	p.header = header
+	p.w = parquet.NewWriter(p.out,
+		parquet.NewSchema(header.Collection, group),
+		parquet.KeyValueMetadata(SchemaHeaderKey, string(meta)),
+	)
+	return nil
+}
+
+// WriteRows 逐行写入Parquet记录
+func (p *parquetWriter) WriteRows(columns []column.Column) error {
+	if p.w == nil {
+		return errors.New("schema header not written")
+	}
+	aligned := alignColumns(p.header, columns)
+	for row := 0; row < rowCount(columns); row++ {
+		record := make(map[string]any, len(aligned))
+		for i, col := range aligned {
+			name := p.header.Fields[i].Name
+			value, err := cellValue(col, row)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read field %s", name)
+			}
+			if record[name], err = parquetValue(p.types[i], value); err != nil {
+				return errors.Wrapf(err, "failed to encode field %s", name)
+			}
+		}
+		if err := p.w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close 写入文件尾部
+func (p *parquetWriter) Close() error {
+	if p.w == nil {
+		return nil
+	}
+	return p.w.Close()
+}
+
+// parquetNode 返回字段类型对应的Parquet节点
+func parquetNode(dataType entity.FieldType, elementType string) (parquet.Node, error) {
+	switch dataType {
+	case entity.FieldTypeBool:
+		return parquet.Leaf(parquet.BooleanType), nil
+	case entity.FieldTypeInt8, entity.FieldTypeInt16, entity.FieldTypeInt32:
+		return parquet.Int(32), nil
+	case entity.FieldTypeInt64:
+		return parquet.Int(64), nil
+	case entity.FieldTypeFloat:
+		return parquet.Leaf(parquet.FloatType), nil
+	case entity.FieldTypeDouble:
+		return parquet.Leaf(parquet.DoubleType), nil
+	case entity.FieldTypeString, entity.FieldTypeVarChar:
+		return parquet.String(), nil
+	case entity.FieldTypeJSON, entity.FieldTypeSparseVector:
+		return parquet.JSON(), nil
+	case entity.FieldTypeFloatVector:
+		return parquet.List(parquet.Leaf(parquet.FloatType)), nil
+	case entity.FieldTypeInt8Vector:
+		return parquet.List(parquet.Int(32)), nil
+	case entity.FieldTypeBinaryVector, entity.FieldTypeFloat16Vector, entity.FieldTypeBFloat16Vector:
+		return parquet.Leaf(parquet.ByteArrayType), nil
+	case entity.FieldTypeArray:
+		element, err := ParseFieldType(elementType)
+		if err != nil {
+			return nil, err
+		}
+		if element == entity.FieldTypeArray {
+			return nil, errors.New("nested array is not supported")
+		}
+		node, err := parquetNode(element, "")
+		if err != nil {
+			return nil, err
+		}
+		return parquet.List(node), nil
+	default:
+		return nil, fmt.Errorf("unsupported field type %s", FieldTypeName(dataType))
+	}
+}
+
+// parquetValue 将通用表示转换为与Parquet节点类型匹配的Go值
+func parquetValue(dataType entity.FieldType, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch dataType {
+	case entity.FieldTypeInt8, entity.FieldTypeInt16, entity.FieldTypeInt32:
+		return toInt32(value)
+	case entity.FieldTypeJSON:
+		if raw, ok := value.(json.RawMessage); ok {
+			return []byte(raw), nil
+		}
+		return json.Marshal(value)
+	case entity.FieldTypeSparseVector:
+		return json.Marshal(value)
+	case entity.FieldTypeInt8Vector:
+		return toInt32(value)
+	case entity.FieldTypeArray:
+		switch v := value.(type) {
+		case []int8, []int16:
+			return toInt32(v)
+		}
+	}
+	return value, nil
+}
+
+// toInt32 将小整数及其切片统一转换为int32表示
+func toInt32(value any) (any, error) {
+	switch v := value.(type) {
+	case int8:
+		return int32(v), nil
+	case int16:
+		return int32(v), nil
+	case int32:
+		return v, nil
+	case []int8:
+		out := make([]int32, len(v))
+		for i, x := range v {
+			out[i] = int32(x)
+		}
+		return out, nil
+	case []int16:
+		out := make([]int32, len(v))
+		for i, x := range v {
+			out[i] = int32(x)
+		}
+		return out, nil
+	case []int32:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unexpected integer value %T", value)
+	}
+}
@@ -0,0 +1,325 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/pkg/errors"
+)
+
+// JSONLReader JSON Lines 读取器，读取由JSONL写入器生成的文件并还原为列式数据
+type JSONLReader struct {
+	r      *bufio.Reader
+	header *SchemaHeader
+	line   int
+}
+
+// NewJSONLReader 创建JSON Lines读取器，会立即读取并解析首行的模式头
+// r: 输入，例如*os.File
+func NewJSONLReader(r io.Reader) (*JSONLReader, error) {
+	reader := &JSONLReader{r: bufio.NewReader(r)}
+
+	data, err := reader.readLine()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("missing schema header")
+		}
+		return nil, err
+	}
+	var wrapper map[string]*SchemaHeader
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, errors.Wrap(err, "failed to decode schema header")
+	}
+	if reader.header = wrapper[SchemaHeaderKey]; reader.header == nil {
+		return nil, errors.Errorf("missing %q in header line", SchemaHeaderKey)
+	}
+	return reader, nil
+}
+
+// Header 返回文件的模式头
+func (r *JSONLReader) Header() *SchemaHeader {
+	return r.header
+}
+
+// Read 读取最多batchSize行并转换为列数据，列顺序与模式头字段一致
+// 数据读完后返回io.EOF
+func (r *JSONLReader) Read(batchSize int) ([]column.Column, error) {
+	builders := make([]columnBuilder, len(r.header.Fields))
+	for i, f := range r.header.Fields {
+		b, err := newColumnBuilder(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %s", f.Name)
+		}
+		builders[i] = b
+	}
+
+	rows := 0
+	for rows < batchSize {
+		data, err := r.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var record map[string]json.RawMessage
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, errors.Wrapf(err, "line %d", r.line)
+		}
+		for i, f := range r.header.Fields {
+			if err := builders[i].append(record[f.Name]); err != nil {
+				return nil, errors.Wrapf(err, "line %d field %s", r.line, f.Name)
+			}
+		}
+		rows++
+	}
+	if rows == 0 {
+		return nil, io.EOF
+	}
+
+	columns := make([]column.Column, len(builders))
+	for i, b := range builders {
+		col, err := b.build()
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %s", r.header.Fields[i].Name)
+		}
+		columns[i] = col
+	}
+	return columns, nil
+}
+
+// readLine 读取下一个非空行
+func (r *JSONLReader) readLine() ([]byte, error) {
+	for {
+		data, err := r.r.ReadBytes('\n')
+		if len(data) > 0 || err == nil {
+			r.line++
+		}
+		data = bytes.TrimSpace(data)
+		if len(data) > 0 {
+			return data, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// columnBuilder 按行累积单元格并构建列
+type columnBuilder interface {
+	append(raw json.RawMessage) error
+	build() (column.Column, error)
+}
+
+// typedBuilder 泛型列构建器，可空列以稀疏模式记录空值
+type typedBuilder[T any] struct {
+	name      string
+	nullable  bool
+	values    []T
+	validData []bool
+	parse     func(raw json.RawMessage) (T, error)
+	create    func(name string, values []T) column.Column
+	nullables func(name string, values []T, validData []bool, opts ...column.ColumnOption[T]) (column.Column, error)
+}
+
+func (b *typedBuilder[T]) append(raw json.RawMessage) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		if !b.nullable {
+			return errors.New("null value for non-nullable field")
+		}
+		var zero T
+		b.values = append(b.values, zero)
+		b.validData = append(b.validData, false)
+		return nil
+	}
+	v, err := b.parse(raw)
+	if err != nil {
+		return err
+	}
+	b.values = append(b.values, v)
+	b.validData = append(b.validData, true)
+	return nil
+}
+
+func (b *typedBuilder[T]) build() (column.Column, error) {
+	if b.nullable && b.nullables != nil {
+		return b.nullables(b.name, b.values, b.validData, column.WithSparseNullableMode[T](true))
+	}
+	return b.create(b.name, b.values), nil
+}
+
+// newTypedBuilder 创建使用json.Unmarshal解析单元格的构建器
+func newTypedBuilder[T any, C column.Column, N column.Column](
+	f FieldHeader,
+	create func(string, []T) C,
+	nullable func(string, []T, []bool, ...column.ColumnOption[T]) (N, error),
+) *typedBuilder[T] {
+	b := &typedBuilder[T]{
+		name:     f.Name,
+		nullable: f.Nullable,
+		parse:    unmarshalValue[T],
+		create: func(name string, values []T) column.Column {
+			return create(name, values)
+		},
+	}
+	if nullable != nil {
+		b.nullables = func(name string, values []T, validData []bool, opts ...column.ColumnOption[T]) (column.Column, error) {
+			return nullable(name, values, validData, opts...)
+		}
+	}
+	return b
+}
+
+// newVectorBuilder 创建向量列构建器，向量字段不支持空值
+func newVectorBuilder[T any, C column.Column](f FieldHeader, parse func(json.RawMessage) (T, error), create func(string, int, []T) C) (*typedBuilder[T], error) {
+	dim, err := strconv.Atoi(f.TypeParams["dim"])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid dim")
+	}
+	return &typedBuilder[T]{
+		name:  f.Name,
+		parse: parse,
+		create: func(name string, values []T) column.Column {
+			return create(name, dim, values)
+		},
+	}, nil
+}
+
+func unmarshalValue[T any](raw json.RawMessage) (T, error) {
+	var v T
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+// decodeBytes 解析base64编码的二进制数据
+func decodeBytes(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// decodeSparse 解析"位置->值"形式的稀疏向量
+func decodeSparse(raw json.RawMessage) (entity.SparseEmbedding, error) {
+	var m map[string]float32
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	positions := make([]uint32, 0, len(m))
+	values := make([]float32, 0, len(m))
+	for k, v := range m {
+		pos, err := strconv.ParseUint(k, 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid sparse position %q", k)
+		}
+		positions = append(positions, uint32(pos))
+		values = append(values, v)
+	}
+	return entity.NewSliceSparseEmbedding(positions, values)
+}
+
+// newColumnBuilder 根据字段描述创建列构建器
+func newColumnBuilder(f FieldHeader) (columnBuilder, error) {
+	dataType, err := ParseFieldType(f.DataType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch dataType {
+	case entity.FieldTypeBool:
+		return newTypedBuilder(f, column.NewColumnBool, column.NewNullableColumnBool), nil
+	case entity.FieldTypeInt8:
+		return newTypedBuilder(f, column.NewColumnInt8, column.NewNullableColumnInt8), nil
+	case entity.FieldTypeInt16:
+		return newTypedBuilder(f, column.NewColumnInt16, column.NewNullableColumnInt16), nil
+	case entity.FieldTypeInt32:
+		return newTypedBuilder(f, column.NewColumnInt32, column.NewNullableColumnInt32), nil
+	case entity.FieldTypeInt64:
+		return newTypedBuilder(f, column.NewColumnInt64, column.NewNullableColumnInt64), nil
+	case entity.FieldTypeFloat:
+		return newTypedBuilder(f, column.NewColumnFloat, column.NewNullableColumnFloat), nil
+	case entity.FieldTypeDouble:
+		return newTypedBuilder(f, column.NewColumnDouble, column.NewNullableColumnDouble), nil
+	case entity.FieldTypeVarChar:
+		return newTypedBuilder(f, column.NewColumnVarChar, column.NewNullableColumnVarChar), nil
+	case entity.FieldTypeString:
+		return newTypedBuilder(f, column.NewColumnString, column.NewNullableColumnString), nil
+	case entity.FieldTypeJSON:
+		b := newTypedBuilder(f, column.NewColumnJSONBytes, column.NewNullableColumnJSONBytes)
+		b.parse = func(raw json.RawMessage) ([]byte, error) {
+			return append([]byte(nil), raw...), nil
+		}
+		if f.IsDynamic {
+			// 动态字段的空值写为空对象，并标记为动态列
+			b.nullable = true
+			b.nullables = nil
+			b.create = func(name string, values [][]byte) column.Column {
+				for i, v := range values {
+					if v == nil {
+						values[i] = []byte("{}")
+					}
+				}
+				return column.NewColumnJSONBytes(name, values).WithIsDynamic(true)
+			}
+		}
+		return b, nil
+	case entity.FieldTypeArray:
+		return newArrayBuilder(f)
+	case entity.FieldTypeFloatVector:
+		return newVectorBuilder(f, unmarshalValue[[]float32], column.NewColumnFloatVector)
+	case entity.FieldTypeInt8Vector:
+		return newVectorBuilder(f, unmarshalValue[[]int8], column.NewColumnInt8Vector)
+	case entity.FieldTypeBinaryVector:
+		return newVectorBuilder(f, decodeBytes, column.NewColumnBinaryVector)
+	case entity.FieldTypeFloat16Vector:
+		return newVectorBuilder(f, decodeBytes, column.NewColumnFloat16Vector)
+	case entity.FieldTypeBFloat16Vector:
+		return newVectorBuilder(f, decodeBytes, column.NewColumnBFloat16Vector)
+	case entity.FieldTypeSparseVector:
+		return &typedBuilder[entity.SparseEmbedding]{
+			name:  f.Name,
+			parse: decodeSparse,
+			create: func(name string, values []entity.SparseEmbedding) column.Column {
+				return column.NewColumnSparseVectors(name, values)
+			},
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported field type %s", f.DataType)
+	}
+}
+
+// newArrayBuilder 根据元素类型创建数组列构建器
+func newArrayBuilder(f FieldHeader) (columnBuilder, error) {
+	elementType, err := ParseFieldType(f.ElementType)
+	if err != nil {
+		return nil, err
+	}
+	switch elementType {
+	case entity.FieldTypeBool:
+		return newTypedBuilder(f, column.NewColumnBoolArray, column.NewNullableColumnBoolArray), nil
+	case entity.FieldTypeInt8:
+		return newTypedBuilder(f, column.NewColumnInt8Array, column.NewNullableColumnInt8Array), nil
+	case entity.FieldTypeInt16:
+		return newTypedBuilder(f, column.NewColumnInt16Array, column.NewNullableColumnInt16Array), nil
+	case entity.FieldTypeInt32:
+		return newTypedBuilder(f, column.NewColumnInt32Array, column.NewNullableColumnInt32Array), nil
+	case entity.FieldTypeInt64:
+		return newTypedBuilder(f, column.NewColumnInt64Array, column.NewNullableColumnInt64Array), nil
+	case entity.FieldTypeFloat:
+		return newTypedBuilder(f, column.NewColumnFloatArray, column.NewNullableColumnFloatArray), nil
+	case entity.FieldTypeDouble:
+		return newTypedBuilder(f, column.NewColumnDoubleArray, column.NewNullableColumnDoubleArray), nil
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		return newTypedBuilder(f, column.NewColumnVarCharArray, column.NewNullableColumnVarCharArray), nil
+	default:
+		return nil, errors.Errorf("unsupported array element type %s", f.ElementType)
+	}
+}
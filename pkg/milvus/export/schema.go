@@ -0,0 +1,281 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/client/v2/entity"
+)
+
+// DynamicFieldName 动态字段在导出文件中的列名，与Milvus内部的动态字段名称保持一致
+const DynamicFieldName = "$meta"
+
+// SchemaHeader 导出文件的模式头，描述集合结构，可序列化为JSON写入文件头部
+type SchemaHeader struct {
+	Collection         string        `json:"collection"`
+	Description        string        `json:"description,omitempty"`
+	AutoID             bool          `json:"auto_id"`
+	EnableDynamicField bool          `json:"enable_dynamic_field"`
+	Partition          string        `json:"partition,omitempty"`
+	Fields             []FieldHeader `json:"fields"`
+}
+
+// FieldHeader 字段描述，类型使用可读名称，例如"Int64"、"FloatVector"
+type FieldHeader struct {
+	Name            string            `json:"name"`
+	DataType        string            `json:"data_type"`
+	ElementType     string            `json:"element_type,omitempty"`
+	Description     string            `json:"description,omitempty"`
+	PrimaryKey      bool              `json:"primary_key,omitempty"`
+	AutoID          bool              `json:"auto_id,omitempty"`
+	IsDynamic       bool              `json:"is_dynamic,omitempty"`
+	IsPartitionKey  bool              `json:"is_partition_key,omitempty"`
+	IsClusteringKey bool              `json:"is_clustering_key,omitempty"`
+	Nullable        bool              `json:"nullable,omitempty"`
+	DefaultValue    any               `json:"default_value,omitempty"` // 默认值，bool、数值或字符串
+	TypeParams      map[string]string `json:"type_params,omitempty"`
+}
+
+// fieldTypes 可读类型名称到字段类型的映射
+var fieldTypes = map[string]entity.FieldType{}
+
+func init() {
+	for _, t := range []entity.FieldType{
+		entity.FieldTypeBool,
+		entity.FieldTypeInt8,
+		entity.FieldTypeInt16,
+		entity.FieldTypeInt32,
+		entity.FieldTypeInt64,
+		entity.FieldTypeFloat,
+		entity.FieldTypeDouble,
+		entity.FieldTypeString,
+		entity.FieldTypeVarChar,
+		entity.FieldTypeArray,
+		entity.FieldTypeJSON,
+		entity.FieldTypeBinaryVector,
+		entity.FieldTypeFloatVector,
+		entity.FieldTypeFloat16Vector,
+		entity.FieldTypeBFloat16Vector,
+		entity.FieldTypeSparseVector,
+		entity.FieldTypeInt8Vector,
+	} {
+		fieldTypes[FieldTypeName(t)] = t
+	}
+}
+
+// FieldTypeName 返回字段类型的可读名称，补充SDK未命名的稀疏向量类型
+func FieldTypeName(t entity.FieldType) string {
+	if t == entity.FieldTypeSparseVector {
+		return "SparseFloatVector"
+	}
+	return t.Name()
+}
+
+// ParseFieldType 将可读类型名称解析为字段类型
+// name: 类型名称，例如"Int64"、"FloatVector"
+func ParseFieldType(name string) (entity.FieldType, error) {
+	if t, ok := fieldTypes[name]; ok {
+		return t, nil
+	}
+	return entity.FieldTypeNone, fmt.Errorf("unsupported field type %q", name)
+}
+
+// NewSchemaHeader 根据集合模式构建模式头
+// schema: 集合模式定义
+// outputFields: 导出的字段名称列表，空表示导出全部字段
+func NewSchemaHeader(schema *entity.Schema, outputFields []string) *SchemaHeader {
+	header := &SchemaHeader{
+		Collection:         schema.CollectionName,
+		Description:        schema.Description,
+		AutoID:             schema.AutoID,
+		EnableDynamicField: schema.EnableDynamicField,
+	}
+
+	selected := make(map[string]bool, len(outputFields))
+	for _, name := range outputFields {
+		selected[name] = true
+	}
+
+	for _, field := range schema.Fields {
+		if len(selected) > 0 && !selected[field.Name] && !field.PrimaryKey {
+			continue
+		}
+		fh := FieldHeader{
+			Name:            field.Name,
+			DataType:        FieldTypeName(field.DataType),
+			Description:     field.Description,
+			PrimaryKey:      field.PrimaryKey,
+			AutoID:          field.AutoID,
+			IsDynamic:       field.IsDynamic,
+			IsPartitionKey:  field.IsPartitionKey,
+			IsClusteringKey: field.IsClusteringKey,
+			Nullable:        field.Nullable,
+			DefaultValue:    defaultValue(field.DefaultValue),
+			TypeParams:      field.TypeParams,
+		}
+		if field.DataType == entity.FieldTypeArray {
+			fh.ElementType = FieldTypeName(field.ElementType)
+		}
+		header.Fields = append(header.Fields, fh)
+	}
+
+	// 动态字段以JSON列的形式导出
+	if schema.EnableDynamicField && (len(selected) == 0 || selected[DynamicFieldName]) {
+		header.Fields = append(header.Fields, FieldHeader{
+			Name:      DynamicFieldName,
+			DataType:  FieldTypeName(entity.FieldTypeJSON),
+			IsDynamic: true,
+		})
+	}
+	return header
+}
+
+// FieldNames 返回模式头中的字段名称列表
+func (h *SchemaHeader) FieldNames() []string {
+	names := make([]string, 0, len(h.Fields))
+	for _, f := range h.Fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// PrimaryField 返回主键字段，不存在时返回nil
+func (h *SchemaHeader) PrimaryField() *FieldHeader {
+	for i := range h.Fields {
+		if h.Fields[i].PrimaryKey {
+			return &h.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Schema 将模式头还原为集合模式定义，动态字段列不会作为普通字段还原
+func (h *SchemaHeader) Schema() (*entity.Schema, error) {
+	schema := &entity.Schema{
+		CollectionName:     h.Collection,
+		Description:        h.Description,
+		AutoID:             h.AutoID,
+		EnableDynamicField: h.EnableDynamicField,
+	}
+	for _, f := range h.Fields {
+		if f.IsDynamic {
+			continue
+		}
+		dataType, err := ParseFieldType(f.DataType)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		field := &entity.Field{
+			Name:            f.Name,
+			DataType:        dataType,
+			Description:     f.Description,
+			PrimaryKey:      f.PrimaryKey,
+			AutoID:          f.AutoID,
+			IsPartitionKey:  f.IsPartitionKey,
+			IsClusteringKey: f.IsClusteringKey,
+			Nullable:        f.Nullable,
+			TypeParams:      f.TypeParams,
+		}
+		if f.ElementType != "" {
+			elementType, err := ParseFieldType(f.ElementType)
+			if err != nil {
+				return nil, fmt.Errorf("field %s element: %w", f.Name, err)
+			}
+			field.ElementType = elementType
+		}
+		if f.DefaultValue != nil {
+			if err := setDefaultValue(field, f.DefaultValue); err != nil {
+				return nil, fmt.Errorf("field %s default value: %w", f.Name, err)
+			}
+		}
+		schema.Fields = append(schema.Fields, field)
+	}
+	return schema, nil
+}
+
+// defaultValue 返回字段默认值的通用表示，未设置默认值时返回nil
+func defaultValue(v *schemapb.ValueField) any {
+	switch d := v.GetData().(type) {
+	case *schemapb.ValueField_BoolData:
+		return d.BoolData
+	case *schemapb.ValueField_IntData:
+		return d.IntData
+	case *schemapb.ValueField_LongData:
+		return d.LongData
+	case *schemapb.ValueField_FloatData:
+		return d.FloatData
+	case *schemapb.ValueField_DoubleData:
+		return d.DoubleData
+	case *schemapb.ValueField_StringData:
+		return d.StringData
+	default:
+		return nil
+	}
+}
+
+// setDefaultValue 按字段类型设置默认值，value可以是defaultValue的返回值或其JSON解码结果
+func setDefaultValue(field *entity.Field, value any) error {
+	switch field.DataType {
+	case entity.FieldTypeBool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected bool, got %T", value)
+		}
+		field.WithDefaultValueBool(b)
+	case entity.FieldTypeString, entity.FieldTypeVarChar:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected string, got %T", value)
+		}
+		field.WithDefaultValueString(s)
+	case entity.FieldTypeInt8, entity.FieldTypeInt16, entity.FieldTypeInt32,
+		entity.FieldTypeInt64, entity.FieldTypeFloat, entity.FieldTypeDouble:
+		n, ok := number(value)
+		if !ok {
+			return fmt.Errorf("expected number, got %T", value)
+		}
+		switch field.DataType {
+		case entity.FieldTypeInt64:
+			l, ok := value.(int64) // 未经JSON编码的int64保持原有精度
+			if !ok {
+				l = int64(n)
+			}
+			field.WithDefaultValueLong(l)
+		case entity.FieldTypeFloat:
+			field.WithDefaultValueFloat(float32(n))
+		case entity.FieldTypeDouble:
+			field.WithDefaultValueDouble(n)
+		default:
+			field.WithDefaultValueInt(int32(n))
+		}
+	default:
+		return fmt.Errorf("default value is not supported for %s fields", FieldTypeName(field.DataType))
+	}
+	return nil
+}
+
+// number 将数值转换为float64，JSON解码后的数值均为float64
+func number(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// quoteString 将字符串转义为Milvus表达式中的字符串字面量
+func quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,123 @@
+package export
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+)
+
+// rowCount 返回一批列数据的行数，以最长的列为准
+func rowCount(columns []column.Column) int {
+	n := 0
+	for _, col := range columns {
+		if col != nil && col.Len() > n {
+			n = col.Len()
+		}
+	}
+	return n
+}
+
+// alignColumns 按模式头的字段顺序排列列数据，缺失的列以nil占位
+func alignColumns(header *SchemaHeader, columns []column.Column) []column.Column {
+	byName := make(map[string]column.Column, len(columns))
+	for _, col := range columns {
+		byName[col.Name()] = col
+	}
+	aligned := make([]column.Column, len(header.Fields))
+	for i, f := range header.Fields {
+		aligned[i] = byName[f.Name]
+	}
+	return aligned
+}
+
+// cellValue 读取单元格的值并转换为与JSON兼容的通用表示
+// 向量转换为切片，二进制向量保持[]byte，稀疏向量转换为"位置->值"的映射，JSON字段保持原始JSON
+func cellValue(col column.Column, idx int) (any, error) {
+	if col == nil || idx >= col.Len() {
+		return nil, nil
+	}
+	if col.Nullable() {
+		isNull, err := col.IsNull(idx)
+		if err != nil {
+			return nil, err
+		}
+		if isNull {
+			return nil, nil
+		}
+	}
+
+	value, err := col.Get(idx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case entity.FloatVector:
+		return []float32(v), nil
+	case entity.BinaryVector:
+		return []byte(v), nil
+	case entity.Float16Vector:
+		return []byte(v), nil
+	case entity.BFloat16Vector:
+		return []byte(v), nil
+	case entity.Int8Vector:
+		return []int8(v), nil
+	case entity.SparseEmbedding:
+		return sparseToMap(v), nil
+	case []byte:
+		if col.Type() == entity.FieldTypeJSON {
+			if len(v) == 0 {
+				return nil, nil
+			}
+			return json.RawMessage(v), nil
+		}
+		return v, nil
+	}
+	return value, nil
+}
+
+// sparseToMap 将稀疏向量转换为"位置->值"的映射
+func sparseToMap(sparse entity.SparseEmbedding) map[string]float32 {
+	m := make(map[string]float32, sparse.Len())
+	for i := 0; i < sparse.Len(); i++ {
+		pos, val, ok := sparse.Get(i)
+		if !ok {
+			continue
+		}
+		m[strconv.FormatUint(uint64(pos), 10)] = val
+	}
+	return m
+}
+
+// textValue 将通用表示转换为CSV单元格文本
+// 标量直接格式化，二进制数据使用base64编码，其余复合值使用JSON编码
+func textValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int8, int16, int32, int64:
+		return fmt.Sprintf("%d", v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case json.RawMessage:
+		return string(v), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v), nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
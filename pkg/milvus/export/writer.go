@@ -0,0 +1,164 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/pkg/errors"
+)
+
+// Format 导出文件格式
+type Format string
+
+const (
+	FormatJSONL   Format = "jsonl"   // JSON Lines，首行为模式头，其后每行一条记录
+	FormatCSV     Format = "csv"     // CSV，首行为字段名称，复合值使用JSON编码
+	FormatParquet Format = "parquet" // Parquet，模式头写入文件元数据
+)
+
+// SchemaHeaderKey JSONL首行中模式头的键名，同时也是Parquet元数据中模式头的键名
+const SchemaHeaderKey = "milvus.schema"
+
+// Writer 定义导出写入器接口
+// 调用顺序为: WriteHeader 一次，WriteRows 若干次，最后 Close
+type Writer interface {
+	// WriteHeader 写入模式头
+	WriteHeader(header *SchemaHeader) error
+
+	// WriteRows 写入一批列式数据，列按名称与模式头字段对应
+	WriteRows(columns []column.Column) error
+
+	// Close 刷新缓冲区并结束写入，不会关闭底层的io.Writer
+	Close() error
+}
+
+// NewWriter 根据格式创建写入器
+// format: 导出格式，例如FormatJSONL
+// w: 底层输出，例如*os.File
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatJSONL:
+		return NewJSONLWriter(w), nil
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatParquet:
+		return NewParquetWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// jsonlWriter JSON Lines 写入器
+type jsonlWriter struct {
+	w      *bufio.Writer
+	header *SchemaHeader
+}
+
+// NewJSONLWriter 创建JSON Lines写入器
+// 首行为 {"milvus.schema": {...}}，其后每行是一个按模式字段顺序输出的JSON对象
+func NewJSONLWriter(w io.Writer) Writer {
+	return &jsonlWriter{w: bufio.NewWriter(w)}
+}
+
+// WriteHeader 写入模式头行
+func (j *jsonlWriter) WriteHeader(header *SchemaHeader) error {
+	j.header = header
+	data, err := json.Marshal(map[string]*SchemaHeader{SchemaHeaderKey: header})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode schema header")
+	}
+	if _, err := j.w.Write(data); err != nil {
+		return err
+	}
+	return j.w.WriteByte('\n')
+}
+
+// WriteRows 逐行写入JSON对象
+func (j *jsonlWriter) WriteRows(columns []column.Column) error {
+	if j.header == nil {
+		return errors.New("schema header not written")
+	}
+	aligned := alignColumns(j.header, columns)
+	for row := 0; row < rowCount(columns); row++ {
+		j.w.WriteByte('{')
+		for i, col := range aligned {
+			if i > 0 {
+				j.w.WriteByte(',')
+			}
+			value, err := cellValue(col, row)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read field %s", j.header.Fields[i].Name)
+			}
+			key, _ := json.Marshal(j.header.Fields[i].Name)
+			data, err := json.Marshal(value)
+			if err != nil {
+				return errors.Wrapf(err, "failed to encode field %s", j.header.Fields[i].Name)
+			}
+			j.w.Write(key)
+			j.w.WriteByte(':')
+			j.w.Write(data)
+		}
+		j.w.WriteByte('}')
+		if err := j.w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close 刷新缓冲区
+func (j *jsonlWriter) Close() error {
+	return j.w.Flush()
+}
+
+// csvWriter CSV 写入器
+type csvWriter struct {
+	w      *csv.Writer
+	header *SchemaHeader
+}
+
+// NewCSVWriter 创建CSV写入器
+// 首行为字段名称；向量、数组、稀疏向量使用JSON编码，二进制数据使用base64编码，空值输出为空字符串
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+// WriteHeader 写入字段名称行
+func (c *csvWriter) WriteHeader(header *SchemaHeader) error {
+	c.header = header
+	return c.w.Write(header.FieldNames())
+}
+
+// WriteRows 逐行写入CSV记录
+func (c *csvWriter) WriteRows(columns []column.Column) error {
+	if c.header == nil {
+		return errors.New("schema header not written")
+	}
+	aligned := alignColumns(c.header, columns)
+	record := make([]string, len(aligned))
+	for row := 0; row < rowCount(columns); row++ {
+		for i, col := range aligned {
+			value, err := cellValue(col, row)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read field %s", c.header.Fields[i].Name)
+			}
+			if record[i], err = textValue(value); err != nil {
+				return errors.Wrapf(err, "failed to encode field %s", c.header.Fields[i].Name)
+			}
+		}
+		if err := c.w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close 刷新缓冲区
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
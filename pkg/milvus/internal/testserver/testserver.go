@@ -0,0 +1,76 @@
+package testserver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// Options 测试gRPC服务配置
+type Options struct {
+	ServerOptions []grpc.ServerOption                 // gRPC服务选项
+	Register      func(server *grpc.Server)           // 注册服务实现
+	Listener      func(lis net.Listener) net.Listener // 包装监听器
+}
+
+// Option 测试gRPC服务配置选项
+type Option func(*Options)
+
+// WithServerOptions 添加gRPC服务选项
+// opts: gRPC服务选项
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(o *Options) {
+		o.ServerOptions = append(o.ServerOptions, opts...)
+	}
+}
+
+// WithHandler 使用handler处理所有未注册服务的请求
+// handler: 请求处理函数
+func WithHandler(handler grpc.StreamHandler) Option {
+	return WithServerOptions(grpc.UnknownServiceHandler(handler))
+}
+
+// WithRegister 在服务启动前注册服务实现
+// register: 注册函数
+func WithRegister(register func(server *grpc.Server)) Option {
+	return func(o *Options) {
+		o.Register = register
+	}
+}
+
+// WithListener 包装监听器，用于控制连接的建立
+// wrap: 包装函数，返回的监听器用于接受连接
+func WithListener(wrap func(lis net.Listener) net.Listener) Option {
+	return func(o *Options) {
+		o.Listener = wrap
+	}
+}
+
+// Start 在本地随机端口启动gRPC服务，测试结束时停止，返回服务地址
+// 未注册服务与处理函数时所有调用都返回Unimplemented
+// t: 测试
+// opts: 配置选项
+func Start(t testing.TB, opts ...Option) string {
+	t.Helper()
+	o := &Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	if o.Listener != nil {
+		lis = o.Listener(lis)
+	}
+
+	server := grpc.NewServer(o.ServerOptions...)
+	if o.Register != nil {
+		o.Register(server)
+	}
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return addr
+}
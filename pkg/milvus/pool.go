@@ -3,9 +3,12 @@ package milvus
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
 )
@@ -15,13 +18,13 @@ type Pool interface {
 	// Get 获取指定名称的客户端，如果不存在则返回错误
 	Get(name string) (client.Client, error)
 
-	// MustGet 获取指定名称的客户端，如果不存在则创建新的客户端
-	MustGet(name string, opts ...client.Option) (client.Client, error)
+	// MustGet 获取指定名称的客户端，如果不存在则使用ctx创建新的客户端
+	MustGet(ctx context.Context, name string, opts ...client.Option) (client.Client, error)
 
-	// Add 添加一个新的客户端
-	Add(name string, opts ...client.Option) error
+	// Add 添加一个新的客户端，启用延迟连接时只记录选项，首次Get时再创建
+	Add(ctx context.Context, name string, opts ...client.Option) error
 
-	// Remove 移除一个客户端
+	// Remove 移除一个客户端，排空进行中的操作后关闭
 	Remove(name string) error
 
 	// Has 检查是否存在指定名称的客户端
@@ -30,87 +33,366 @@ type Pool interface {
 	// List 列出所有已添加的客户端名称
 	List() []string
 
-	// Close 关闭所有客户端连接
+	// Health 获取指定名称的客户端的健康状态
+	Health(name string) (HealthStatus, error)
+
+	// Config 获取指定名称的客户端的生效配置
+	Config(name string) (*client.Options, error)
+
+	// Reload 按新配置重建有变化的客户端，排空旧客户端进行中的操作后关闭
+	Reload(ctx context.Context, cfg *Config) (*ReloadResult, error)
+
+	// Close 关闭所有客户端连接，关闭后不能再添加客户端
 	Close() error
 }
 
+// PoolOptions 定义连接池的配置选项
+type PoolOptions struct {
+	Name              string                // 连接池名称，作为连接池指标的pool标签，同一注册器上的多个连接池需要使用不同的名称
+	MetricsRegisterer prometheus.Registerer // Prometheus指标注册器，非nil时记录连接池与客户端指标
+	ClientOptions     []client.Option       // 追加到每个客户端的选项，在添加客户端时的选项之前应用
+
+	// 连接配置
+	LazyConnect    bool          // Add时不连接，首次Get时再创建客户端
+	ConnectTimeout time.Duration // 延迟创建与重建客户端的超时时间
+
+	// 重新加载配置
+	DrainTimeout time.Duration // 替换或移除客户端时等待进行中的操作完成的最长时间
+
+	// 健康检查配置
+	HealthCheckInterval time.Duration // 后台健康检查间隔，0表示不启用
+	HealthCheckTimeout  time.Duration // 单次健康检查的超时时间
+	UnhealthyThreshold  int           // 连续健康检查失败达到该次数后使用原始选项重建客户端
+}
+
+// DefaultPoolOptions 返回默认的连接池配置
+func DefaultPoolOptions() *PoolOptions {
+	return &PoolOptions{
+		Name:               "default",        // 默认连接池名称
+		ConnectTimeout:     10 * time.Second, // 默认连接超时10秒
+		DrainTimeout:       30 * time.Second, // 默认最多等待旧客户端的操作30秒
+		HealthCheckTimeout: 5 * time.Second,  // 默认健康检查超时5秒
+		UnhealthyThreshold: 3,                // 默认连续失败3次后重建
+	}
+}
+
+// PoolOption 定义连接池配置选项函数类型
+type PoolOption func(*PoolOptions)
+
+// WithPoolMetrics 启用Prometheus指标
+// 注册连接池的客户端数量与健康状态指标，并为连接池创建的客户端启用客户端指标，client标签为客户端名称
+// reg: 指标注册器，多个连接池共用时需要通过WithPoolName设置不同的名称；连接池关闭时注销其指标
+func WithPoolMetrics(reg prometheus.Registerer) PoolOption {
+	return func(o *PoolOptions) {
+		o.MetricsRegisterer = reg
+	}
+}
+
+// WithPoolName 设置连接池名称，作为连接池指标的pool标签
+// name: 同一注册器上的多个连接池需要使用不同的名称，默认"default"
+func WithPoolName(name string) PoolOption {
+	return func(o *PoolOptions) {
+		o.Name = name
+	}
+}
+
+// WithClientOptions 设置连接池中所有客户端共用的选项
+// 这些选项在添加客户端时的选项之前应用，可以被单个客户端的选项覆盖，
+// 适合设置配置文件无法表示的选项，例如client.WithLogger、client.WithTracing
+func WithClientOptions(opts ...client.Option) PoolOption {
+	return func(o *PoolOptions) {
+		o.ClientOptions = append(o.ClientOptions, opts...)
+	}
+}
+
+// WithLazyConnect 启用延迟连接
+// Add只记录客户端选项，首次Get时再创建客户端，适合启动时服务端可能尚未就绪的场景
+// timeout: 延迟创建与重建客户端的超时时间，0表示使用默认值10秒
+func WithLazyConnect(timeout time.Duration) PoolOption {
+	return func(o *PoolOptions) {
+		o.LazyConnect = true
+		if timeout > 0 {
+			o.ConnectTimeout = timeout
+		}
+	}
+}
+
+// WithDrainTimeout 设置重新加载配置时等待旧客户端排空的最长时间
+// 超时后Reload不再等待，旧客户端在进行中的操作结束后于后台关闭
+// timeout: 最长等待时间，默认30秒
+func WithDrainTimeout(timeout time.Duration) PoolOption {
+	return func(o *PoolOptions) {
+		o.DrainTimeout = timeout
+	}
+}
+
+// WithHealthCheck 启用后台健康检查
+// 定期检查每个客户端，连续失败达到阈值时使用添加客户端时的选项重建客户端并关闭旧客户端，
+// 因此调用方应在使用时通过Get获取客户端，不要长期持有
+// interval: 检查间隔，例如30*time.Second，0表示不启用
+// timeout: 单次检查的超时时间，0表示使用默认值5秒
+// threshold: 连续失败多少次后重建客户端，0表示使用默认值3
+func WithHealthCheck(interval, timeout time.Duration, threshold int) PoolOption {
+	return func(o *PoolOptions) {
+		o.HealthCheckInterval = interval
+		if timeout > 0 {
+			o.HealthCheckTimeout = timeout
+		}
+		if threshold > 0 {
+			o.UnhealthyThreshold = threshold
+		}
+	}
+}
+
 // pool 实现 Pool 接口
 type pool struct {
-	clients map[string]client.Client
+	entries map[string]*entry
+	options *PoolOptions
 	mu      sync.RWMutex
+
+	connecting map[string]*connectCall // 正在创建的客户端，同一名称同时只创建一次
+	closed     bool                    // 连接池是否已关闭，关闭后不能再添加客户端
+	reloadMu   sync.Mutex              // 保证同一时间只有一次重新加载
+
+	collector prometheus.Collector // 连接池指标采集器，未启用指标时为nil，关闭时注销
+
+	stop     chan struct{} // 关闭时通知健康检查退出
+	done     chan struct{} // 健康检查退出后关闭，未启用健康检查时为nil
+	stopOnce sync.Once
+}
+
+// connectCall 一次进行中的客户端创建，并发获取同一名称的调用方共享结果
+type connectCall struct {
+	done chan struct{} // 创建完成后关闭
+	cli  client.Client
+	err  error
+}
+
+// wait 等待创建完成，ctx结束时提前返回，不影响进行中的创建
+func (c *connectCall) wait(ctx context.Context) (client.Client, error) {
+	select {
+	case <-c.done:
+		return c.cli, c.err
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "failed to wait for client creation")
+	}
 }
 
 // NewPool 创建一个新的 Milvus 客户端连接池
-func NewPool() Pool {
-	return &pool{
-		clients: make(map[string]client.Client),
+// 启用指标时注册失败会panic，与prometheus.MustRegister一致，例如同一注册器上已有同名的连接池，需要处理错误时使用NewPoolE
+func NewPool(opts ...PoolOption) Pool {
+	p, err := NewPoolE(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// NewPoolE 创建一个新的 Milvus 客户端连接池，启用指标时注册失败返回错误
+// opts: 连接池配置选项
+func NewPoolE(opts ...PoolOption) (Pool, error) {
+	p, err := newPool(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// newPool 创建连接池，启用指标时注册失败返回错误
+func newPool(opts ...PoolOption) (*pool, error) {
+	options := DefaultPoolOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	p := &pool{
+		entries:    make(map[string]*entry),
+		connecting: make(map[string]*connectCall),
+		options:    options,
+		stop:       make(chan struct{}),
+	}
+	if options.MetricsRegisterer != nil {
+		p.collector = newPoolCollector(p)
+		if err := options.MetricsRegisterer.Register(p.collector); err != nil {
+			return nil, errors.Wrapf(err, "failed to register metrics of pool %s", options.Name)
+		}
+	}
+	if options.HealthCheckInterval > 0 {
+		p.done = make(chan struct{})
+		go p.healthCheckLoop()
 	}
+	return p, nil
 }
 
-// Get 获取指定名称的客户端
+// Get 获取指定名称的客户端，启用延迟连接时首次获取会创建客户端
 func (p *pool) Get(name string) (client.Client, error) {
 	p.mu.RLock()
-	defer p.mu.RUnlock()
+	e, ok := p.entries[name]
+	var cli client.Client
+	if ok {
+		cli = e.cli
+	}
+	p.mu.RUnlock()
 
-	if cli, ok := p.clients[name]; ok {
+	if !ok {
+		return nil, fmt.Errorf("client %s not found", name)
+	}
+	if cli != nil {
 		return cli, nil
 	}
-	return nil, fmt.Errorf("client %s not found", name)
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.options.ConnectTimeout)
+	defer cancel()
+	return p.connect(ctx, name, false, nil)
 }
 
 // MustGet 获取指定名称的客户端，如果不存在则创建新的客户端
-func (p *pool) MustGet(name string, opts ...client.Option) (client.Client, error) {
-	// 先尝试获取已存在的客户端
-	if cli, err := p.Get(name); err == nil {
-		return cli, nil
-	}
-
-	// 如果不存在，则创建新的客户端
-	return p.add(name, opts...)
+// 与Add不同，即使启用了延迟连接也会立即创建客户端；并发调用同一名称时共享一次创建
+func (p *pool) MustGet(ctx context.Context, name string, opts ...client.Option) (client.Client, error) {
+	return p.connect(ctx, name, true, opts)
 }
 
 // Add 添加一个新的客户端
-func (p *pool) Add(name string, opts ...client.Option) error {
-	_, err := p.add(name, opts...)
+func (p *pool) Add(ctx context.Context, name string, opts ...client.Option) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return errors.New("pool is closed")
+	}
+	// 检查客户端是否已存在或正在创建
+	if _, exists := p.entries[name]; exists || p.connecting[name] != nil {
+		p.mu.Unlock()
+		return fmt.Errorf("client %s already exists", name)
+	}
+
+	e := p.newEntry(name, opts)
+	if p.options.LazyConnect {
+		// 延迟连接时只记录选项
+		p.entries[name] = e
+		p.mu.Unlock()
+		return nil
+	}
+	call := p.startConnect(name)
+	p.mu.Unlock()
+
+	_, err := p.finishConnect(ctx, name, e, call, true)
 	return err
 }
 
-// add 内部方法，添加一个新的客户端
-func (p *pool) add(name string, opts ...client.Option) (client.Client, error) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// newEntry 创建连接池条目，记录完整选项与生效配置
+func (p *pool) newEntry(name string, opts []client.Option) *entry {
+	// 记录客户端的健康状态，启用指标时开启客户端指标，之后依次是共用选项与用户选项，后者可以覆盖前者
+	e := &entry{status: HealthStatus{Healthy: true}}
+	poolOpts := []client.Option{client.WithInterceptors(healthInterceptor(e))}
+	if p.options.MetricsRegisterer != nil {
+		poolOpts = append(poolOpts,
+			client.WithMetrics(p.options.MetricsRegisterer, name),
+			client.WithMetricsLabels(prometheus.Labels{"pool": p.options.Name}),
+		)
+	}
+	poolOpts = append(poolOpts, p.options.ClientOptions...)
+	e.opts = append(poolOpts, opts...)
 
-	// 检查客户端是否已存在
-	if _, exists := p.clients[name]; exists {
-		return nil, fmt.Errorf("client %s already exists", name)
+	e.options = client.DefaultOptions()
+	for _, opt := range e.opts {
+		opt(e.options)
 	}
+	return e
+}
+
+// connect 返回指定名称的客户端，必要时创建
+// 已连接时直接返回；同一名称正在创建时等待并共享结果；延迟连接的条目使用保存的选项创建；
+// 不存在时，create为true则使用opts创建并加入连接池，否则返回错误
+func (p *pool) connect(ctx context.Context, name string, create bool, opts []client.Option) (client.Client, error) {
+	p.mu.Lock()
+	e, exists := p.entries[name]
+	if exists && e.cli != nil {
+		p.mu.Unlock()
+		return e.cli, nil
+	}
+	if call := p.connecting[name]; call != nil {
+		p.mu.Unlock()
+		return call.wait(ctx)
+	}
+	if !exists {
+		if !create {
+			p.mu.Unlock()
+			return nil, fmt.Errorf("client %s not found", name)
+		}
+		if p.closed {
+			p.mu.Unlock()
+			return nil, errors.New("pool is closed")
+		}
+		e = p.newEntry(name, opts)
+	}
+	call := p.startConnect(name)
+	p.mu.Unlock()
+
+	return p.finishConnect(ctx, name, e, call, !exists)
+}
 
-	// 创建新的客户端
-	cli, err := client.NewWithOptions(context.Background(), opts...)
+// startConnect 登记一次客户端创建，调用方需持有连接池的写锁
+func (p *pool) startConnect(name string) *connectCall {
+	call := &connectCall{done: make(chan struct{})}
+	p.connecting[name] = call
+	return call
+}
+
+// finishConnect 在不持有连接池锁的情况下创建客户端，完成后加入连接池并通知等待者
+// isNew: 条目是否为新添加的，为false时条目是已在连接池中的延迟连接条目
+// 创建期间条目被移除或连接池被关闭时，关闭新客户端并返回错误
+func (p *pool) finishConnect(ctx context.Context, name string, e *entry, call *connectCall, isNew bool) (client.Client, error) {
+	cli, err := client.NewWithOptions(ctx, e.opts...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create new client")
+		err = errors.Wrap(err, "failed to create new client")
+	}
+
+	p.mu.Lock()
+	delete(p.connecting, name)
+	if err == nil {
+		switch {
+		case p.closed:
+			err = errors.New("pool is closed")
+		case isNew && p.entries[name] != nil:
+			// 创建期间重新加载配置添加了同名客户端
+			err = fmt.Errorf("client %s already exists", name)
+		case isNew:
+			e.cli = cli
+			p.entries[name] = e
+		case p.entries[name] == e:
+			e.cli = cli
+		default:
+			err = fmt.Errorf("client %s was removed while connecting", name)
+		}
 	}
+	p.mu.Unlock()
 
-	// 添加客户端到连接池
-	p.clients[name] = cli
-	return cli, nil
+	if err != nil && cli != nil {
+		_ = cli.Close()
+		cli = nil
+	}
+	call.cli, call.err = cli, err
+	close(call.done)
+	return cli, err
 }
 
 // Remove 移除一个客户端
+// 移除立即生效，旧客户端等待进行中的操作完成后关闭，最多等待DrainTimeout，超时后在后台关闭；
+// 等待期间不持有连接池的锁，不影响其他客户端的使用
 func (p *pool) Remove(name string) error {
 	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	if cli, ok := p.clients[name]; ok {
-		// 关闭客户端连接
-		if err := cli.Close(); err != nil {
-			return errors.Wrap(err, "failed to close client")
-		}
-		// 从连接池中移除客户端
-		delete(p.clients, name)
-		return nil
+	e, ok := p.entries[name]
+	if !ok {
+		p.mu.Unlock()
+		return fmt.Errorf("client %s not found", name)
 	}
-	return fmt.Errorf("client %s not found", name)
+	// 延迟连接的条目正在创建时，创建完成后发现条目已被移除会关闭新客户端
+	delete(p.entries, name)
+	delete(p.connecting, name)
+	p.mu.Unlock()
+
+	p.retire([]*entry{e})
+	return nil
 }
 
 // Has 检查是否存在指定名称的客户端
@@ -118,7 +400,7 @@ func (p *pool) Has(name string) bool {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	_, exists := p.clients[name]
+	_, exists := p.entries[name]
 	return exists
 }
 
@@ -127,27 +409,81 @@ func (p *pool) List() []string {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	names := make([]string, 0, len(p.clients))
-	for name := range p.clients {
+	names := make([]string, 0, len(p.entries))
+	for name := range p.entries {
 		names = append(names, name)
 	}
 	return names
 }
 
-// Close 关闭所有客户端连接
+// Health 获取指定名称的客户端的健康状态
+func (p *pool) Health(name string) (HealthStatus, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if e, ok := p.entries[name]; ok {
+		return e.health(), nil
+	}
+	return HealthStatus{}, fmt.Errorf("client %s not found", name)
+}
+
+// Config 获取指定名称的客户端的生效配置，即默认配置依次应用连接池与添加时的选项后的结果
+// 返回的是副本，修改不会影响连接池中的客户端
+func (p *pool) Config(name string) (*client.Options, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if e, ok := p.entries[name]; ok {
+		options := *e.options
+		options.RetryPolicies = maps.Clone(options.RetryPolicies)
+		options.RateLimits = maps.Clone(options.RateLimits)
+		options.Timeouts = maps.Clone(options.Timeouts)
+		return &options, nil
+	}
+	return nil, fmt.Errorf("client %s not found", name)
+}
+
+// Close 关闭所有客户端连接，停止后台健康检查并注销连接池指标
 func (p *pool) Close() error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	// 先停止健康检查，重建客户端时需要获取连接池的锁
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		if p.collector != nil {
+			p.options.MetricsRegisterer.Unregister(p.collector)
+		}
+	})
+	if p.done != nil {
+		<-p.done
+	}
 
-	var errs []error
-	for name, cli := range p.clients {
-		if err := cli.Close(); err != nil {
-			errs = append(errs, fmt.Errorf("failed to close client %s: %v", name, err))
+	// 清空客户端映射，正在创建的客户端完成后会被关闭
+	p.mu.Lock()
+	clients := make(map[string]client.Client, len(p.entries))
+	for name, e := range p.entries {
+		if e.cli != nil {
+			clients[name] = e.cli
 		}
 	}
+	p.entries = make(map[string]*entry)
+	p.closed = true
+	p.mu.Unlock()
 
-	// 清空客户端映射
-	p.clients = make(map[string]client.Client)
+	// 客户端关闭时等待进行中的操作完成，不持有连接池的锁并发关闭
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for name, cli := range clients {
+		wg.Add(1)
+		go func(name string, cli client.Client) {
+			defer wg.Done()
+			if err := cli.Close(); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to close client %s: %v", name, err))
+				mu.Unlock()
+			}
+		}(name, cli)
+	}
+	wg.Wait()
 
 	if len(errs) > 0 {
 		return fmt.Errorf("failed to close some clients: %v", errs)
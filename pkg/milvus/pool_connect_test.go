@@ -0,0 +1,240 @@
+package milvus
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// gatedListener 在gate关闭前不接受连接，用于模拟耗时的连接建立
+type gatedListener struct {
+	net.Listener
+	gate      chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+	accepts   atomic.Int32
+}
+
+// Accept 等待gate关闭后接受连接并计数
+func (l *gatedListener) Accept() (net.Conn, error) {
+	select {
+	case <-l.gate:
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.accepts.Add(1)
+	}
+	return conn, err
+}
+
+// Close 关闭监听，唤醒等待gate的Accept
+func (l *gatedListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return l.Listener.Close()
+}
+
+// startGatedServer 启动一个在gate关闭前不接受连接的gRPC服务器
+func startGatedServer(t *testing.T) (string, *gatedListener) {
+	gated := &gatedListener{gate: make(chan struct{}), closed: make(chan struct{})}
+	addr := testserver.Start(t, testserver.WithListener(func(lis net.Listener) net.Listener {
+		gated.Listener = lis
+		return gated
+	}))
+	return addr, gated
+}
+
+// TestPoolConcurrentMustGet 测试并发MustGet共享一次创建，且创建期间不阻塞其他客户端
+func TestPoolConcurrentMustGet(t *testing.T) {
+	slowAddr, gated := startGatedServer(t)
+	fastAddr := testserver.Start(t)
+	pl := NewPool()
+	defer pl.Close()
+
+	const numGoroutines = 10
+	var wg sync.WaitGroup
+	clients := make([]client.Client, numGoroutines)
+	errs := make([]error, numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			clients[i], errs[i] = pl.MustGet(ctx, "slow", client.WithAddress(slowAddr), client.WithDisableConn(true))
+		}(i)
+	}
+
+	t.Run("创建期间其他客户端不受阻塞", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		require.NoError(t, pl.Add(ctx, "fast", client.WithAddress(fastAddr), client.WithDisableConn(true)))
+		_, err := pl.Get("fast")
+		assert.NoError(t, err)
+		assert.False(t, pl.Has("slow"), "创建完成前不加入连接池")
+		assert.ErrorContains(t, pl.Add(ctx, "slow", client.WithAddress(slowAddr)), "already exists")
+	})
+
+	t.Run("并发调用共享一次创建", func(t *testing.T) {
+		close(gated.gate)
+		wg.Wait()
+		for i := 0; i < numGoroutines; i++ {
+			require.NoError(t, errs[i])
+			assert.Same(t, clients[0], clients[i])
+		}
+		assert.Equal(t, int32(1), gated.accepts.Load())
+
+		cli, err := pl.Get("slow")
+		require.NoError(t, err)
+		assert.Same(t, clients[0], cli)
+	})
+
+	t.Run("等待者的ctx结束时提前返回", func(t *testing.T) {
+		addr, _ := startGatedServer(t)
+		first := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			_, err := pl.MustGet(ctx, "blocked", client.WithAddress(addr))
+			first <- err
+		}()
+		require.Eventually(t, func() bool {
+			p := pl.(*pool)
+			p.mu.RLock()
+			defer p.mu.RUnlock()
+			return p.connecting["blocked"] != nil
+		}, time.Second, 5*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := pl.MustGet(ctx, "blocked", client.WithAddress(addr))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		assert.Error(t, <-first)
+		assert.False(t, pl.Has("blocked"), "创建失败时不加入连接池")
+	})
+}
+
+// TestPoolCloseWhileConnecting 测试创建期间关闭连接池
+func TestPoolCloseWhileConnecting(t *testing.T) {
+	addr, gated := startGatedServer(t)
+	pl := NewPool()
+
+	result := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := pl.MustGet(ctx, "main", client.WithAddress(addr), client.WithDisableConn(true))
+		result <- err
+	}()
+	require.Eventually(t, func() bool {
+		p := pl.(*pool)
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		return p.connecting["main"] != nil
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, pl.Close())
+	close(gated.gate)
+	assert.ErrorContains(t, <-result, "pool is closed")
+	assert.Empty(t, pl.List())
+	assert.ErrorContains(t, pl.Add(context.Background(), "other", client.WithAddress(addr)), "pool is closed")
+}
+
+// TestPoolLazyConnect 测试延迟连接
+func TestPoolLazyConnect(t *testing.T) {
+	addr := testserver.Start(t)
+	pl := NewPool(WithLazyConnect(5 * time.Second))
+	defer pl.Close()
+	p := pl.(*pool)
+
+	opts := []client.Option{client.WithAddress(addr), client.WithDisableConn(true)}
+	require.NoError(t, pl.Add(context.Background(), "lazy", opts...))
+
+	t.Run("添加时不创建客户端", func(t *testing.T) {
+		assert.True(t, pl.Has("lazy"))
+		p.mu.RLock()
+		assert.Nil(t, p.entries["lazy"].cli)
+		p.mu.RUnlock()
+	})
+
+	t.Run("首次获取时创建且只创建一次", func(t *testing.T) {
+		var wg sync.WaitGroup
+		clients := make([]client.Client, 5)
+		for i := range clients {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				cli, err := pl.Get("lazy")
+				assert.NoError(t, err)
+				clients[i] = cli
+			}(i)
+		}
+		wg.Wait()
+
+		require.NotNil(t, clients[0])
+		for _, cli := range clients[1:] {
+			assert.Same(t, clients[0], cli)
+		}
+	})
+
+	t.Run("未连接的客户端可以移除", func(t *testing.T) {
+		require.NoError(t, pl.Add(context.Background(), "unused", opts...))
+		require.NoError(t, pl.Remove("unused"))
+		assert.False(t, pl.Has("unused"))
+	})
+
+	t.Run("MustGet立即创建", func(t *testing.T) {
+		cli, err := pl.MustGet(context.Background(), "eager", opts...)
+		require.NoError(t, err)
+		assert.NotNil(t, cli)
+	})
+}
+
+// TestPoolConfig 测试获取客户端的生效配置
+func TestPoolConfig(t *testing.T) {
+	pl := NewPool(WithLazyConnect(0))
+	defer pl.Close()
+
+	require.NoError(t, pl.Add(context.Background(), "main",
+		client.WithAddress("milvus:19530"),
+		client.WithDatabase("db1"),
+		client.WithTimeout(client.OpRead, 5*time.Second),
+	))
+
+	t.Run("合并默认配置与添加时的选项", func(t *testing.T) {
+		cfg, err := pl.Config("main")
+		require.NoError(t, err)
+		assert.Equal(t, "milvus:19530", cfg.Address)
+		assert.Equal(t, "db1", cfg.DBName)
+		assert.Equal(t, 5*time.Second, cfg.Timeouts[client.OpRead])
+		assert.Equal(t, client.DefaultTimeouts()[client.OpWrite], cfg.Timeouts[client.OpWrite])
+	})
+
+	t.Run("返回副本", func(t *testing.T) {
+		cfg, err := pl.Config("main")
+		require.NoError(t, err)
+		cfg.Address = "other:19530"
+		cfg.Timeouts[client.OpRead] = time.Minute
+
+		cfg, err = pl.Config("main")
+		require.NoError(t, err)
+		assert.Equal(t, "milvus:19530", cfg.Address)
+		assert.Equal(t, 5*time.Second, cfg.Timeouts[client.OpRead])
+	})
+
+	t.Run("不存在的客户端", func(t *testing.T) {
+		_, err := pl.Config("missing")
+		assert.Error(t, err)
+	})
+}
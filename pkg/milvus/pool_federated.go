@@ -0,0 +1,257 @@
+package milvus
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/pkg/errors"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+)
+
+// FederatedOptions 联邦搜索的配置选项
+type FederatedOptions struct {
+	Quorum         int           // 至少多少个集群成功时返回合并结果，取值[1, 集群数]
+	ClusterTimeout time.Duration // 每个集群的超时时间，超时的集群计为失败，0表示只使用ctx的截止时间
+}
+
+// DefaultFederatedOptions 返回默认的联邦搜索配置
+func DefaultFederatedOptions() *FederatedOptions {
+	return &FederatedOptions{
+		Quorum: 1, // 默认至少一个集群成功即返回结果
+	}
+}
+
+// FederatedOption 定义联邦搜索配置选项函数类型
+type FederatedOption func(*FederatedOptions)
+
+// WithQuorum 设置至少多少个集群成功时返回合并结果，成功的集群不足时Search返回错误
+// quorum: 取值[1, 集群数]，默认1；等于集群数时任一集群失败即返回错误
+func WithQuorum(quorum int) FederatedOption {
+	return func(o *FederatedOptions) {
+		o.Quorum = quorum
+	}
+}
+
+// WithClusterTimeout 设置每个集群的超时时间，避免一个慢集群拖慢整个搜索
+// timeout: 超时的集群计为失败，0表示只使用ctx的截止时间
+func WithClusterTimeout(timeout time.Duration) FederatedOption {
+	return func(o *FederatedOptions) {
+		o.ClusterTimeout = timeout
+	}
+}
+
+// FederatedHit 联邦搜索的一条命中
+type FederatedHit struct {
+	Cluster string         // 命中所在的集群，即连接池中的客户端名称
+	ID      any            // 主键，int64或string
+	Score   float32        // 集群返回的分数，L2等距离度量越小越相似，IP、COSINE、BM25越大越相似
+	Fields  map[string]any // 输出字段的值
+}
+
+// FederatedResult 联邦搜索的结果
+type FederatedResult struct {
+	Hits     [][]FederatedHit // 每个查询向量的命中，按度量类型从最相似到最不相似排序，最多topK条
+	Clusters []string         // 成功返回结果的集群，按创建时的顺序
+	Failures map[string]error // 失败的集群及其错误，全部成功时为空
+}
+
+// FederatedSearcher 在连接池中的多个集群上并发搜索并合并结果
+type FederatedSearcher struct {
+	pool     Pool
+	clusters []string
+	options  *FederatedOptions
+}
+
+// NewFederatedSearcher 创建联邦搜索，用于数据按地域等维度拆分到多个集群的场景
+//
+// Search并发发送到所有集群，按度量类型合并每个查询向量的前topK条命中，并标记命中所在的集群。
+// 每次搜索时通过Pool.Get获取客户端，因此连接池重建或重新加载客户端后自动使用新客户端。
+// 各集群的集合需要使用相同的度量类型，分数才可以直接比较。
+// p: 连接池
+// clusters: 参与搜索的客户端名称
+// opts: 联邦搜索配置选项
+func NewFederatedSearcher(p Pool, clusters []string, opts ...FederatedOption) (*FederatedSearcher, error) {
+	options := DefaultFederatedOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if len(clusters) == 0 {
+		return nil, errors.New("federated search requires at least one cluster")
+	}
+	if options.Quorum < 1 || options.Quorum > len(clusters) {
+		return nil, errors.Errorf("quorum must be in [1, %d], got %d", len(clusters), options.Quorum)
+	}
+	if options.ClusterTimeout < 0 {
+		return nil, errors.Errorf("cluster timeout must not be negative, got %s", options.ClusterTimeout)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range clusters {
+		if !p.Has(name) {
+			return nil, errors.Errorf("cluster client %s not found", name)
+		}
+		if seen[name] {
+			return nil, errors.Errorf("duplicate cluster client %s", name)
+		}
+		seen[name] = true
+	}
+	return &FederatedSearcher{pool: p, clusters: append([]string(nil), clusters...), options: options}, nil
+}
+
+// clusterResult 一个集群的搜索结果
+type clusterResult struct {
+	index   int
+	results []milvusclient.ResultSet
+	err     error
+}
+
+// Search 在所有集群上并发搜索，合并每个查询向量的前topK条命中
+//
+// 成功的集群达到Quorum时返回合并结果，失败的集群记录在FederatedResult.Failures中；
+// 失败的集群过多、已经无法达到Quorum时取消其他集群的请求并返回错误。参数与client.Client的Search相同。
+// metricType: 决定合并时的排序方向，必须与各集群集合的度量类型一致
+func (s *FederatedSearcher) Search(ctx context.Context, collectionName string, partitionNames []string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, expr string, params map[string]string) (*FederatedResult, error) {
+	largerIsBetter, err := scoreOrder(metricType)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch := make(chan clusterResult, len(s.clusters))
+	for i, name := range s.clusters {
+		go func() {
+			results, err := s.searchCluster(ctx, name, func(ctx context.Context, cli client.Client) ([]milvusclient.ResultSet, error) {
+				return cli.Search(ctx, collectionName, partitionNames, outputFields, vectors, vectorField, metricType, topK, expr, params)
+			})
+			if err == nil && len(results) != len(vectors) {
+				err = errors.Errorf("returned %d result sets for %d query vectors", len(results), len(vectors))
+			}
+			ch <- clusterResult{index: i, results: results, err: err}
+		}()
+	}
+
+	results := make([][]milvusclient.ResultSet, len(s.clusters))
+	errs := make([]error, len(s.clusters))
+	failed := 0
+	for range s.clusters {
+		r := <-ch
+		if r.err != nil {
+			errs[r.index] = r.err
+			failed++
+			if failed > len(s.clusters)-s.options.Quorum {
+				return nil, s.quorumError(errs)
+			}
+			continue
+		}
+		results[r.index] = r.results
+	}
+
+	res := &FederatedResult{Hits: make([][]FederatedHit, len(vectors)), Failures: make(map[string]error)}
+	for i, name := range s.clusters {
+		if errs[i] != nil {
+			res.Failures[name] = errs[i]
+			continue
+		}
+		res.Clusters = append(res.Clusters, name)
+	}
+	for q := range vectors {
+		res.Hits[q] = mergeHits(s.clusters, results, q, topK, largerIsBetter)
+	}
+	return res, nil
+}
+
+// searchCluster 在一个集群上搜索，返回的结果中任一查询向量出错时整个集群计为失败
+func (s *FederatedSearcher) searchCluster(ctx context.Context, name string, search func(ctx context.Context, cli client.Client) ([]milvusclient.ResultSet, error)) ([]milvusclient.ResultSet, error) {
+	cli, err := s.pool.Get(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get cluster client %s", name)
+	}
+	if s.options.ClusterTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.options.ClusterTimeout)
+		defer cancel()
+	}
+	results, err := search(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	for _, rs := range results {
+		if rs.Err != nil {
+			return nil, rs.Err
+		}
+	}
+	return results, nil
+}
+
+// quorumError 返回成功的集群不足时的错误，包装第一个失败集群的错误以便使用errors.Is判断
+func (s *FederatedSearcher) quorumError(errs []error) error {
+	var first error
+	var failures []string
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if first == nil {
+			first = err
+		}
+		failures = append(failures, s.clusters[i]+": "+err.Error())
+	}
+	return errors.Wrapf(first, "federated search quorum not met, need %d of %d clusters (%s)",
+		s.options.Quorum, len(s.clusters), strings.Join(failures, "; "))
+}
+
+// scoreOrder 返回度量类型的分数是否越大越相似
+func scoreOrder(metricType entity.MetricType) (bool, error) {
+	switch metricType {
+	case entity.IP, entity.COSINE, entity.BM25:
+		return true, nil
+	case entity.L2, entity.HAMMING, entity.JACCARD, entity.MHJACCARD, entity.TANIMOTO, entity.SUBSTRUCTURE, entity.SUPERSTRUCTURE:
+		return false, nil
+	case "":
+		return false, errors.New("metric type is required to merge federated search results")
+	default:
+		return false, errors.Errorf("unknown metric type %s", metricType)
+	}
+}
+
+// mergeHits 合并所有成功集群中第q个查询向量的命中，分数相同时按集群顺序与集群内的顺序排列
+func mergeHits(clusters []string, results [][]milvusclient.ResultSet, q, topK int, largerIsBetter bool) []FederatedHit {
+	var hits []FederatedHit
+	for i, name := range clusters {
+		if results[i] == nil {
+			continue
+		}
+		rs := results[i][q]
+		for j := 0; j < rs.ResultCount; j++ {
+			hit := FederatedHit{Cluster: name, Score: rs.Scores[j]}
+			if rs.IDs != nil {
+				hit.ID, _ = rs.IDs.Get(j)
+			}
+			if len(rs.Fields) > 0 {
+				hit.Fields = make(map[string]any, len(rs.Fields))
+				for _, col := range rs.Fields {
+					hit.Fields[col.Name()], _ = col.Get(j)
+				}
+			}
+			hits = append(hits, hit)
+		}
+	}
+
+	sort.SliceStable(hits, func(a, b int) bool {
+		if largerIsBetter {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].Score < hits[b].Score
+	})
+	if topK > 0 && len(hits) > topK {
+		hits = hits[:topK]
+	}
+	return hits
+}
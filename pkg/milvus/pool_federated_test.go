@@ -0,0 +1,229 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+)
+
+// fakeSearchPool 只实现Get与Has的连接池，返回fakeSearchClient
+type fakeSearchPool struct {
+	Pool
+	clients map[string]*fakeSearchClient
+}
+
+// Get 返回指定名称的客户端
+func (p *fakeSearchPool) Get(name string) (client.Client, error) {
+	cli, ok := p.clients[name]
+	if !ok {
+		return nil, client.ErrClosed
+	}
+	return cli, nil
+}
+
+// Has 检查是否存在指定名称的客户端
+func (p *fakeSearchPool) Has(name string) bool {
+	_, ok := p.clients[name]
+	return ok
+}
+
+// fakeSearchClient 返回预设搜索结果的客户端
+type fakeSearchClient struct {
+	client.Client
+	results []milvusclient.ResultSet // 每个查询向量的结果
+	err     error
+	delay   time.Duration // 返回前等待的时间，ctx结束时提前返回ctx的错误
+}
+
+// Search 按设置返回结果
+func (c *fakeSearchClient) Search(ctx context.Context, collectionName string, partitionNames []string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, expr string, params map[string]string) ([]milvusclient.ResultSet, error) {
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return c.results, c.err
+}
+
+// resultSet 创建一个查询向量的结果，包含主键与title输出字段
+func resultSet(ids []int64, scores []float32, titles []string) milvusclient.ResultSet {
+	return milvusclient.ResultSet{
+		ResultCount: len(ids),
+		IDs:         column.NewColumnInt64("id", ids),
+		Scores:      scores,
+		Fields:      milvusclient.DataSet{column.NewColumnVarChar("title", titles)},
+	}
+}
+
+// federatedSearch 通过联邦搜索发起一次搜索
+func federatedSearch(ctx context.Context, s *FederatedSearcher, metric entity.MetricType, topK int, nq int) (*FederatedResult, error) {
+	vectors := make([]entity.Vector, nq)
+	for i := range vectors {
+		vectors[i] = entity.FloatVector{0.1, 0.2}
+	}
+	return s.Search(ctx, "docs", nil, []string{"title"}, vectors, "vector", metric, topK, "", nil)
+}
+
+// hitKeys 返回命中的"集群:主键"列表
+func hitKeys(hits []FederatedHit) []string {
+	keys := make([]string, len(hits))
+	for i, hit := range hits {
+		keys[i] = fmt.Sprintf("%s:%v", hit.Cluster, hit.ID)
+	}
+	return keys
+}
+
+// TestFederatedSearcher 测试跨集群联邦搜索
+func TestFederatedSearcher(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("按距离合并前topK条命中", func(t *testing.T) {
+		pl := &fakeSearchPool{clients: map[string]*fakeSearchClient{
+			"cn": {results: []milvusclient.ResultSet{resultSet([]int64{1, 2, 3}, []float32{0.1, 0.4, 0.9}, []string{"a", "b", "c"})}},
+			"us": {results: []milvusclient.ResultSet{resultSet([]int64{4, 5}, []float32{0.2, 0.3}, []string{"d", "e"})}},
+		}}
+		s, err := NewFederatedSearcher(pl, []string{"cn", "us"})
+		require.NoError(t, err)
+
+		res, err := federatedSearch(ctx, s, entity.L2, 4, 1)
+		require.NoError(t, err)
+		require.Len(t, res.Hits, 1)
+		assert.Equal(t, []string{"cn:1", "us:4", "us:5", "cn:2"}, hitKeys(res.Hits[0]), "L2越小越相似")
+		assert.Equal(t, float32(0.2), res.Hits[0][1].Score)
+		assert.Equal(t, map[string]any{"title": "d"}, res.Hits[0][1].Fields)
+		assert.Equal(t, []string{"cn", "us"}, res.Clusters)
+		assert.Empty(t, res.Failures)
+	})
+
+	t.Run("按相似度合并多个查询向量", func(t *testing.T) {
+		pl := &fakeSearchPool{clients: map[string]*fakeSearchClient{
+			"cn": {results: []milvusclient.ResultSet{
+				resultSet([]int64{1, 2}, []float32{0.9, 0.5}, []string{"a", "b"}),
+				resultSet([]int64{3}, []float32{0.3}, []string{"c"}),
+			}},
+			"us": {results: []milvusclient.ResultSet{
+				resultSet([]int64{4}, []float32{0.7}, []string{"d"}),
+				resultSet([]int64{5, 6}, []float32{0.8, 0.3}, []string{"e", "f"}),
+			}},
+		}}
+		s, err := NewFederatedSearcher(pl, []string{"cn", "us"})
+		require.NoError(t, err)
+
+		res, err := federatedSearch(ctx, s, entity.COSINE, 10, 2)
+		require.NoError(t, err)
+		require.Len(t, res.Hits, 2)
+		assert.Equal(t, []string{"cn:1", "us:4", "cn:2"}, hitKeys(res.Hits[0]), "COSINE越大越相似")
+		assert.Equal(t, []string{"us:5", "cn:3", "us:6"}, hitKeys(res.Hits[1]), "分数相同时按集群顺序")
+	})
+
+	t.Run("部分集群失败时返回其他集群的结果", func(t *testing.T) {
+		pl := &fakeSearchPool{clients: map[string]*fakeSearchClient{
+			"cn": {results: []milvusclient.ResultSet{resultSet([]int64{1}, []float32{0.5}, []string{"a"})}},
+			"us": {err: client.ErrUnavailable},
+			"eu": {results: []milvusclient.ResultSet{resultSet([]int64{2}, []float32{0.6}, []string{"b"})}},
+		}}
+		s, err := NewFederatedSearcher(pl, []string{"cn", "us", "eu"}, WithQuorum(2))
+		require.NoError(t, err)
+
+		res, err := federatedSearch(ctx, s, entity.IP, 10, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"eu:2", "cn:1"}, hitKeys(res.Hits[0]))
+		assert.Equal(t, []string{"cn", "eu"}, res.Clusters)
+		require.Contains(t, res.Failures, "us")
+		assert.ErrorIs(t, res.Failures["us"], client.ErrUnavailable)
+	})
+
+	t.Run("成功的集群不足时返回错误", func(t *testing.T) {
+		pl := &fakeSearchPool{clients: map[string]*fakeSearchClient{
+			"cn": {results: []milvusclient.ResultSet{resultSet([]int64{1}, []float32{0.5}, []string{"a"})}},
+			"us": {err: client.ErrUnavailable},
+			"eu": {results: []milvusclient.ResultSet{{Err: client.ErrNotLoaded}}},
+		}}
+		s, err := NewFederatedSearcher(pl, []string{"cn", "us", "eu"}, WithQuorum(2))
+		require.NoError(t, err)
+
+		_, err = federatedSearch(ctx, s, entity.L2, 10, 1)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "quorum not met, need 2 of 3 clusters")
+		assert.ErrorContains(t, err, "us: ")
+		assert.ErrorContains(t, err, "eu: ")
+		assert.ErrorIs(t, err, client.ErrUnavailable)
+	})
+
+	t.Run("无法达到法定数时不等待其他集群", func(t *testing.T) {
+		pl := &fakeSearchPool{clients: map[string]*fakeSearchClient{
+			"cn": {delay: time.Hour},
+			"us": {err: client.ErrUnavailable},
+		}}
+		s, err := NewFederatedSearcher(pl, []string{"cn", "us"}, WithQuorum(2))
+		require.NoError(t, err)
+
+		start := time.Now()
+		_, err = federatedSearch(ctx, s, entity.L2, 10, 1)
+		assert.ErrorIs(t, err, client.ErrUnavailable)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("超时的集群计为失败", func(t *testing.T) {
+		pl := &fakeSearchPool{clients: map[string]*fakeSearchClient{
+			"cn": {results: []milvusclient.ResultSet{resultSet([]int64{1}, []float32{0.5}, []string{"a"})}},
+			"us": {delay: time.Hour},
+		}}
+		s, err := NewFederatedSearcher(pl, []string{"cn", "us"}, WithClusterTimeout(50*time.Millisecond))
+		require.NoError(t, err)
+
+		res, err := federatedSearch(ctx, s, entity.L2, 10, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cn:1"}, hitKeys(res.Hits[0]))
+		assert.ErrorIs(t, res.Failures["us"], context.DeadlineExceeded)
+	})
+
+	t.Run("结果数量与查询向量不一致", func(t *testing.T) {
+		pl := &fakeSearchPool{clients: map[string]*fakeSearchClient{
+			"cn": {results: []milvusclient.ResultSet{resultSet([]int64{1}, []float32{0.5}, []string{"a"})}},
+		}}
+		s, err := NewFederatedSearcher(pl, []string{"cn"})
+		require.NoError(t, err)
+		_, err = federatedSearch(ctx, s, entity.L2, 10, 2)
+		assert.ErrorContains(t, err, "returned 1 result sets for 2 query vectors")
+	})
+
+	t.Run("无效的度量类型", func(t *testing.T) {
+		pl := &fakeSearchPool{clients: map[string]*fakeSearchClient{"cn": {}}}
+		s, err := NewFederatedSearcher(pl, []string{"cn"})
+		require.NoError(t, err)
+		_, err = federatedSearch(ctx, s, "", 10, 1)
+		assert.ErrorContains(t, err, "metric type is required")
+		_, err = federatedSearch(ctx, s, "DOT", 10, 1)
+		assert.ErrorContains(t, err, "unknown metric type DOT")
+	})
+
+	t.Run("无效配置", func(t *testing.T) {
+		pl := &fakeSearchPool{clients: map[string]*fakeSearchClient{"cn": {}, "us": {}}}
+		tests := []struct {
+			clusters []string
+			opts     []FederatedOption
+			want     string
+		}{
+			{nil, nil, "at least one cluster"},
+			{[]string{"cn", "missing"}, nil, "cluster client missing not found"},
+			{[]string{"cn", "cn"}, nil, "duplicate cluster client cn"},
+			{[]string{"cn", "us"}, []FederatedOption{WithQuorum(3)}, "quorum must be in [1, 2], got 3"},
+			{[]string{"cn", "us"}, []FederatedOption{WithQuorum(0)}, "quorum must be in [1, 2], got 0"},
+			{[]string{"cn"}, []FederatedOption{WithClusterTimeout(-time.Second)}, "cluster timeout"},
+		}
+		for _, tt := range tests {
+			_, err := NewFederatedSearcher(pl, tt.clusters, tt.opts...)
+			assert.ErrorContains(t, err, tt.want)
+		}
+	})
+}
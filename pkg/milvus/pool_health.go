@@ -0,0 +1,182 @@
+package milvus
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+)
+
+// HealthStatus 连接池中客户端的健康状态
+type HealthStatus struct {
+	Healthy             bool      // 最近一次健康检查或操作是否连接正常
+	LastCheck           time.Time // 最近一次健康检查的时间，未检查过时为零值
+	LastError           error     // 最近一次失败的错误，之后恢复健康时保留
+	ConsecutiveFailures int       // 连续健康检查失败次数
+	Reconnects          int       // 重建客户端的次数
+}
+
+// entry 连接池中的一个客户端，记录创建选项以便重建
+type entry struct {
+	opts    []client.Option // 创建客户端的完整选项，包含连接池追加的选项
+	options *client.Options // 应用opts后的生效配置
+	cli     client.Client   // 当前客户端，由连接池的锁保护，延迟连接且尚未获取时为nil
+	config  *ClientConfig   // 来自配置文件时的客户端配置，用于重新加载时比较，通过Add添加时为nil
+	active  atomic.Int64    // 进行中的操作数，替换客户端前等待其归零
+
+	mu     sync.Mutex
+	status HealthStatus
+}
+
+// health 返回健康状态的副本
+func (e *entry) health() HealthStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status
+}
+
+// observe 根据客户端操作的结果更新健康状态
+// 操作成功时标记为健康，服务不可用时标记为不健康，其他错误（如集合不存在）说明服务端可达，不改变状态
+func (e *entry) observe(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch {
+	case err == nil:
+		e.status.Healthy = true
+		e.status.ConsecutiveFailures = 0
+	case errors.Is(err, client.ErrUnavailable):
+		e.status.Healthy = false
+		e.status.LastError = err
+	}
+}
+
+// probed 记录一次健康检查的结果，返回连续失败次数
+func (e *entry) probed(err error, now time.Time) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.status.LastCheck = now
+	if err == nil {
+		e.status.Healthy = true
+		e.status.ConsecutiveFailures = 0
+		return 0
+	}
+	e.status.Healthy = false
+	e.status.LastError = err
+	e.status.ConsecutiveFailures++
+	return e.status.ConsecutiveFailures
+}
+
+// drain 等待进行中的操作完成，最多等待timeout
+func (e *entry) drain(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for e.active.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// healthInterceptor 根据客户端每次操作的结果更新健康状态，并记录进行中的操作数
+func healthInterceptor(e *entry) client.Interceptor {
+	return func(ctx context.Context, info *client.OperationInfo, invoker client.Invoker) error {
+		e.active.Add(1)
+		defer e.active.Add(-1)
+		err := invoker(ctx, info)
+		e.observe(err)
+		return err
+	}
+}
+
+// healthCheckLoop 按间隔检查所有客户端，直到连接池关闭
+func (p *pool) healthCheckLoop() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.options.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.checkAll()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// checkAll 并发检查所有客户端，一个客户端无响应不会延迟其他客户端的检查
+// 延迟连接且尚未创建的客户端不检查
+func (p *pool) checkAll() {
+	p.mu.RLock()
+	targets := make(map[string]*entry, len(p.entries))
+	clients := make(map[string]client.Client, len(p.entries))
+	for name, e := range p.entries {
+		if e.cli == nil {
+			continue
+		}
+		targets[name] = e
+		clients[name] = e.cli
+	}
+	p.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for name, e := range targets {
+		wg.Add(1)
+		go func(name string, e *entry, cli client.Client) {
+			defer wg.Done()
+			p.check(name, e, cli)
+		}(name, e, clients[name])
+	}
+	wg.Wait()
+}
+
+// check 检查一个客户端，连续失败达到阈值时重建
+func (p *pool) check(name string, e *entry, cli client.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.options.HealthCheckTimeout)
+	defer cancel()
+
+	if failures := e.probed(cli.CheckHealth(ctx), time.Now()); failures >= p.options.UnhealthyThreshold {
+		p.recreate(name, e, cli)
+	}
+}
+
+// recreate 使用原始选项重建客户端，替换成功后旧客户端在后台排空后关闭，不阻塞健康检查
+// 检查期间客户端已被移除或替换时放弃新客户端
+func (p *pool) recreate(name string, e *entry, old client.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.options.ConnectTimeout)
+	defer cancel()
+
+	cli, err := client.NewWithOptions(ctx, e.opts...)
+	if err != nil {
+		e.mu.Lock()
+		e.status.LastError = errors.Wrap(err, "failed to recreate client")
+		e.mu.Unlock()
+		return
+	}
+
+	p.mu.Lock()
+	replaced := p.entries[name] == e && e.cli == old
+	if replaced {
+		e.cli = cli
+	}
+	p.mu.Unlock()
+
+	if !replaced {
+		_ = cli.Close()
+		return
+	}
+	// 与Reload替换客户端相同，最多等待DrainTimeout；进行中的操作数包含新客户端的操作，因此可能等满DrainTimeout
+	go func() {
+		e.drain(p.options.DrainTimeout)
+		closeDrained(e, old)
+	}()
+
+	e.mu.Lock()
+	e.status.Healthy = true
+	e.status.ConsecutiveFailures = 0
+	e.status.Reconnects++
+	e.mu.Unlock()
+}
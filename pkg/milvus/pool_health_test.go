@@ -0,0 +1,153 @@
+package milvus
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// healthServer 只实现CheckHealth的Milvus服务，可切换健康状态
+type healthServer struct {
+	milvuspb.UnimplementedMilvusServiceServer
+	unhealthy atomic.Bool
+	checks    atomic.Int32
+}
+
+// CheckHealth 返回当前的健康状态
+func (s *healthServer) CheckHealth(ctx context.Context, req *milvuspb.CheckHealthRequest) (*milvuspb.CheckHealthResponse, error) {
+	s.checks.Add(1)
+	if s.unhealthy.Load() {
+		return &milvuspb.CheckHealthResponse{IsHealthy: false, Reasons: []string{"querynode down"}}, nil
+	}
+	return &milvuspb.CheckHealthResponse{IsHealthy: true}, nil
+}
+
+// startHealthServer 启动只实现CheckHealth的gRPC服务器
+func startHealthServer(t *testing.T) (string, *healthServer) {
+	srv := &healthServer{}
+	addr := testserver.Start(t, testserver.WithRegister(func(server *grpc.Server) {
+		milvuspb.RegisterMilvusServiceServer(server, srv)
+	}))
+	return addr, srv
+}
+
+// TestPoolHealthCheck 测试连接池的健康检查与自动重建
+func TestPoolHealthCheck(t *testing.T) {
+	addr, srv := startHealthServer(t)
+	pl := NewPool(WithHealthCheck(20*time.Millisecond, time.Second, 2))
+	defer pl.Close()
+
+	require.NoError(t, pl.Add(context.Background(), "main", client.WithAddress(addr), client.WithDisableConn(true), client.WithRetryPolicy(client.OpRead, nil)))
+	original, err := pl.Get("main")
+	require.NoError(t, err)
+
+	t.Run("定期检查", func(t *testing.T) {
+		require.Eventually(t, func() bool {
+			status, err := pl.Health("main")
+			return err == nil && !status.LastCheck.IsZero()
+		}, 5*time.Second, 10*time.Millisecond)
+
+		status, err := pl.Health("main")
+		require.NoError(t, err)
+		assert.True(t, status.Healthy)
+		assert.Zero(t, status.ConsecutiveFailures)
+	})
+
+	t.Run("不健康时使用原始选项重建", func(t *testing.T) {
+		srv.unhealthy.Store(true)
+		require.Eventually(t, func() bool {
+			status, _ := pl.Health("main")
+			return status.Reconnects > 0
+		}, 5*time.Second, 10*time.Millisecond)
+
+		status, err := pl.Health("main")
+		require.NoError(t, err)
+		assert.ErrorIs(t, status.LastError, client.ErrUnavailable)
+		assert.Contains(t, status.LastError.Error(), "querynode down")
+
+		current, err := pl.Get("main")
+		require.NoError(t, err)
+		assert.NotSame(t, original, current)
+		require.Eventually(t, func() bool {
+			return errors.Is(original.CheckHealth(context.Background()), client.ErrClosed)
+		}, 5*time.Second, 10*time.Millisecond, "旧客户端排空后关闭")
+
+		srv.unhealthy.Store(false)
+		require.Eventually(t, func() bool {
+			status, _ := pl.Health("main")
+			return status.Healthy && status.ConsecutiveFailures == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.NoError(t, current.CheckHealth(context.Background()), "重建的客户端连接到同一服务")
+	})
+
+	t.Run("不存在的客户端", func(t *testing.T) {
+		_, err := pl.Health("missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("关闭后停止检查", func(t *testing.T) {
+		require.NoError(t, pl.Close())
+		checks := srv.checks.Load()
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, checks, srv.checks.Load())
+	})
+}
+
+// TestPoolHealthCheckDrain 测试重建客户端时旧客户端在后台排空，不阻塞健康检查与关闭连接池
+func TestPoolHealthCheckDrain(t *testing.T) {
+	addr, srv := startHealthServer(t)
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	pl := NewPool(WithHealthCheck(20*time.Millisecond, time.Second, 1), WithDrainTimeout(time.Minute),
+		WithClientOptions(client.WithInterceptors(func(ctx context.Context, info *client.OperationInfo, invoker client.Invoker) error {
+			if info.Name == "ListCollections" {
+				started <- struct{}{}
+				<-release
+			}
+			return invoker(ctx, info)
+		})))
+	require.NoError(t, pl.Add(context.Background(), "main", client.WithAddress(addr), client.WithDisableConn(true), client.WithRetryPolicy(client.OpRead, nil)))
+	original, err := pl.Get("main")
+	require.NoError(t, err)
+
+	inflight := make(chan error, 1)
+	go func() {
+		_, err := original.ListCollections(context.Background())
+		inflight <- err
+	}()
+	<-started
+
+	srv.unhealthy.Store(true)
+	require.Eventually(t, func() bool {
+		status, _ := pl.Health("main")
+		return status.Reconnects > 0
+	}, 5*time.Second, 10*time.Millisecond, "旧客户端的操作进行中时仍完成重建")
+
+	// 健康检查继续进行
+	checks := srv.checks.Load()
+	require.Eventually(t, func() bool { return srv.checks.Load() > checks }, 5*time.Second, 10*time.Millisecond)
+
+	closed := make(chan error, 1)
+	go func() { closed <- pl.Close() }()
+	select {
+	case err := <-closed:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("旧客户端排空期间关闭连接池不应阻塞")
+	}
+
+	close(release)
+	<-inflight
+	require.Eventually(t, func() bool {
+		return errors.Is(original.CheckHealth(context.Background()), client.ErrClosed)
+	}, 5*time.Second, 10*time.Millisecond)
+}
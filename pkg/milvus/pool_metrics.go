@@ -0,0 +1,52 @@
+package milvus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector 在采集时读取连接池状态，移除的客户端不会残留指标
+// 指标带有连接池名称的pool标签，同一注册器上可以注册多个名称不同的连接池
+type poolCollector struct {
+	pool    *pool
+	clients *prometheus.Desc
+	healthy *prometheus.Desc
+}
+
+// newPoolCollector 创建连接池指标采集器
+func newPoolCollector(p *pool) prometheus.Collector {
+	labels := prometheus.Labels{"pool": p.options.Name}
+	return &poolCollector{
+		pool: p,
+		clients: prometheus.NewDesc(
+			"milvus_pool_clients",
+			"Number of clients in the Milvus pool.",
+			nil, labels,
+		),
+		healthy: prometheus.NewDesc(
+			"milvus_pool_client_healthy",
+			"Whether a pooled Milvus client is healthy (1) according to its last health check or operation, or not (0).",
+			[]string{"client"}, labels,
+		),
+	}
+}
+
+// Describe 实现prometheus.Collector
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.clients
+	ch <- c.healthy
+}
+
+// Collect 实现prometheus.Collector
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	c.pool.mu.RLock()
+	defer c.pool.mu.RUnlock()
+
+	ch <- prometheus.MustNewConstMetric(c.clients, prometheus.GaugeValue, float64(len(c.pool.entries)))
+	for name, e := range c.pool.entries {
+		value := 0.0
+		if e.health().Healthy {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.healthy, prometheus.GaugeValue, value, name)
+	}
+}
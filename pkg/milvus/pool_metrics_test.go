@@ -0,0 +1,133 @@
+package milvus
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// TestPoolMetrics 测试连接池指标
+func TestPoolMetrics(t *testing.T) {
+	addr := testserver.Start(t)
+	reg := prometheus.NewRegistry()
+	pl := NewPool(WithPoolMetrics(reg))
+	defer pl.Close()
+
+	err := pl.Add(context.Background(), "main", client.WithAddress(addr), client.WithDisableConn(true), client.WithRetryPolicy(client.OpRead, nil))
+	require.NoError(t, err)
+
+	expected := `
+# HELP milvus_pool_client_healthy Whether a pooled Milvus client is healthy (1) according to its last health check or operation, or not (0).
+# TYPE milvus_pool_client_healthy gauge
+milvus_pool_client_healthy{client="main",pool="default"} 1
+# HELP milvus_pool_clients Number of clients in the Milvus pool.
+# TYPE milvus_pool_clients gauge
+milvus_pool_clients{pool="default"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "milvus_pool_clients", "milvus_pool_client_healthy"))
+
+	t.Run("客户端指标使用客户端名称与连接池标签", func(t *testing.T) {
+		cli, err := pl.Get("main")
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err = cli.HasCollection(ctx, "c1")
+		assert.ErrorIs(t, err, client.ErrUnsupported)
+
+		expected := `
+# HELP milvus_client_operation_errors_total Failed Milvus client operations by error code and kind.
+# TYPE milvus_client_operation_errors_total counter
+milvus_client_operation_errors_total{client="main",code="Unimplemented",kind="unsupported",operation="HasCollection",pool="default"} 1
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "milvus_client_operation_errors_total"))
+	})
+
+	t.Run("服务不可用时标记为不健康", func(t *testing.T) {
+		p := pl.(*pool)
+		p.mu.RLock()
+		e := p.entries["main"]
+		p.mu.RUnlock()
+
+		interceptor := healthInterceptor(e)
+		unavailable := &client.Error{Kind: client.ErrUnavailable, Err: status.Error(codes.Unavailable, "down")}
+		_ = interceptor(context.Background(), &client.OperationInfo{}, func(ctx context.Context, info *client.OperationInfo) error {
+			return unavailable
+		})
+		assert.False(t, e.health().Healthy)
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP milvus_pool_client_healthy Whether a pooled Milvus client is healthy (1) according to its last health check or operation, or not (0).
+# TYPE milvus_pool_client_healthy gauge
+milvus_pool_client_healthy{client="main",pool="default"} 0
+`), "milvus_pool_client_healthy"))
+
+		_ = interceptor(context.Background(), &client.OperationInfo{}, func(ctx context.Context, info *client.OperationInfo) error {
+			return nil
+		})
+		assert.True(t, e.health().Healthy)
+	})
+
+	t.Run("移除客户端后不再上报", func(t *testing.T) {
+		require.NoError(t, pl.Remove("main"))
+		expected := `
+# HELP milvus_pool_clients Number of clients in the Milvus pool.
+# TYPE milvus_pool_clients gauge
+milvus_pool_clients{pool="default"} 0
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "milvus_pool_clients", "milvus_pool_client_healthy"))
+	})
+
+	t.Run("同一注册器上的多个连接池", func(t *testing.T) {
+		other := NewPool(WithPoolMetrics(reg), WithPoolName("analytics"))
+		require.NoError(t, other.Add(context.Background(), "main", client.WithAddress(addr), client.WithDisableConn(true), client.WithRetryPolicy(client.OpRead, nil)))
+		cli, err := other.Get("main")
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err = cli.HasCollection(ctx, "c1")
+		assert.ErrorIs(t, err, client.ErrUnsupported)
+
+		expected := `
+# HELP milvus_pool_clients Number of clients in the Milvus pool.
+# TYPE milvus_pool_clients gauge
+milvus_pool_clients{pool="analytics"} 1
+milvus_pool_clients{pool="default"} 0
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "milvus_pool_clients"))
+		expected = `
+# HELP milvus_client_operation_errors_total Failed Milvus client operations by error code and kind.
+# TYPE milvus_client_operation_errors_total counter
+milvus_client_operation_errors_total{client="main",code="Unimplemented",kind="unsupported",operation="HasCollection",pool="analytics"} 1
+milvus_client_operation_errors_total{client="main",code="Unimplemented",kind="unsupported",operation="HasCollection",pool="default"} 1
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "milvus_client_operation_errors_total"), "同名客户端按连接池区分")
+
+		_, err = NewPoolE(WithPoolMetrics(reg), WithPoolName("analytics"))
+		assert.ErrorContains(t, err, "failed to register metrics of pool analytics")
+		_, err = NewPoolFromConfig(context.Background(), &Config{Pool: DefaultPoolOptions()}, WithPoolMetrics(reg), WithPoolName("analytics"))
+		assert.ErrorContains(t, err, "failed to register metrics of pool analytics", "同名连接池返回错误而不是panic")
+		assert.Panics(t, func() { NewPool(WithPoolMetrics(reg), WithPoolName("analytics")) })
+
+		require.NoError(t, other.Close())
+		expected = `
+# HELP milvus_pool_clients Number of clients in the Milvus pool.
+# TYPE milvus_pool_clients gauge
+milvus_pool_clients{pool="default"} 0
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "milvus_pool_clients"), "关闭后注销指标")
+
+		recreated, err := NewPoolFromConfig(context.Background(), &Config{Pool: DefaultPoolOptions()}, WithPoolMetrics(reg), WithPoolName("analytics"))
+		require.NoError(t, err, "关闭后可以重新创建同名连接池")
+		require.NoError(t, recreated.Close())
+	})
+}
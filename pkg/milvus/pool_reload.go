@@ -0,0 +1,176 @@
+package milvus
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+)
+
+// ReloadResult 重新加载配置的结果，名称均已排序
+type ReloadResult struct {
+	Added   []string // 新增的客户端
+	Updated []string // 配置变化而重建的客户端
+	Removed []string // 配置中已删除而移除的客户端
+}
+
+// Reload 按新配置更新连接池中来自配置文件的客户端
+//
+// 新增配置中新出现的客户端，重建配置有变化的客户端，移除配置中已删除的客户端，配置未变化的客户端保持不变。
+// 通过Add添加的客户端不受影响，除非新配置中有同名客户端，此时替换为配置中的客户端。
+// 配置中的pool部分只在创建连接池时生效，重新加载时忽略。
+//
+// 先创建所有新客户端，任一创建失败时关闭已创建的客户端并返回错误，连接池保持不变；
+// 全部创建成功后一次性替换，之后Get返回新客户端。被替换与移除的旧客户端等待进行中的操作完成后关闭，
+// Reload最多等待DrainTimeout，超时后旧客户端在后台关闭。替换后通过旧客户端发起的操作会返回client.ErrClosed，
+// 因此调用方应在使用时通过Get获取客户端，不要长期持有。
+// ctx: 创建新客户端使用的上下文，启用延迟连接时不使用
+func (p *pool) Reload(ctx context.Context, cfg *Config) (*ReloadResult, error) {
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+
+	// 计算差异
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return nil, errors.New("pool is closed")
+	}
+	result := &ReloadResult{}
+	changed := make(map[string]*entry)
+	for _, name := range cfg.Names() {
+		cc := cfg.Clients[name]
+		old, exists := p.entries[name]
+		switch {
+		case !exists:
+			result.Added = append(result.Added, name)
+		case old.config != nil && reflect.DeepEqual(old.config.values, cc.values):
+			continue
+		default:
+			result.Updated = append(result.Updated, name)
+		}
+		e := p.newEntry(name, []client.Option{cc.Option()})
+		e.config = cc
+		changed[name] = e
+	}
+	removed := make(map[string]*entry)
+	for name, e := range p.entries {
+		if _, ok := cfg.Clients[name]; !ok && e.config != nil {
+			result.Removed = append(result.Removed, name)
+			removed[name] = e
+		}
+	}
+	sort.Strings(result.Removed)
+	p.mu.RUnlock()
+
+	// 创建新客户端，期间不持有连接池的锁
+	if !p.options.LazyConnect {
+		if err := connectAll(ctx, changed); err != nil {
+			return nil, err
+		}
+	}
+
+	// 一次性替换
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		closeAll(changed)
+		return nil, errors.New("pool is closed")
+	}
+	var retired []*entry
+	for name, e := range changed {
+		if old, ok := p.entries[name]; ok {
+			retired = append(retired, old)
+		}
+		p.entries[name] = e
+	}
+	for name, e := range removed {
+		// 重新加载期间已被移除的客户端无需处理
+		if p.entries[name] == e {
+			retired = append(retired, e)
+			delete(p.entries, name)
+		}
+	}
+	p.mu.Unlock()
+
+	// 排空后关闭旧客户端
+	p.retire(retired)
+	return result, nil
+}
+
+// connectAll 并发为条目创建客户端，任一失败时关闭已创建的客户端并返回指明客户端的错误
+func connectAll(ctx context.Context, entries map[string]*entry) error {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, e *entry) {
+			defer wg.Done()
+			cli, err := client.NewWithOptions(ctx, e.opts...)
+			if err != nil {
+				errs[i] = errors.Wrap(err, "failed to create new client")
+				return
+			}
+			e.cli = cli
+		}(i, entries[name])
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			closeAll(entries)
+			return &ConfigError{Key: joinKey("clients", names[i]), Err: err}
+		}
+	}
+	return nil
+}
+
+// closeAll 关闭尚未加入连接池的条目的客户端
+func closeAll(entries map[string]*entry) {
+	for _, e := range entries {
+		if e.cli != nil {
+			_ = e.cli.Close()
+			e.cli = nil
+		}
+	}
+}
+
+// retire 并发等待旧客户端进行中的操作完成后关闭，最多等待DrainTimeout
+// 超时后仍有操作进行中时不再等待，客户端在后台关闭，关闭会等到这些操作结束
+func (p *pool) retire(entries []*entry) {
+	var wg sync.WaitGroup
+	for _, e := range entries {
+		wg.Add(1)
+		go func(e *entry) {
+			defer wg.Done()
+			e.drain(p.options.DrainTimeout)
+
+			// 延迟连接的条目可能在排空期间被创建，读取时需要持有连接池的锁
+			p.mu.RLock()
+			cli := e.cli
+			p.mu.RUnlock()
+			closeDrained(e, cli)
+		}(e)
+	}
+	wg.Wait()
+}
+
+// closeDrained 关闭排空后的旧客户端，仍有操作进行中时在后台关闭，关闭会等到这些操作结束
+func closeDrained(e *entry, cli client.Client) {
+	switch {
+	case cli == nil:
+	case e.active.Load() == 0:
+		_ = cli.Close()
+	default:
+		go cli.Close()
+	}
+}
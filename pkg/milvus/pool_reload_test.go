@@ -0,0 +1,255 @@
+package milvus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// reloadConfig 生成指向addr的配置，clients为客户端名称到数据库名称的映射
+func reloadConfig(t *testing.T, addr string, clients map[string]string) *Config {
+	data := "clients:\n"
+	for name, db := range clients {
+		data += fmt.Sprintf("  %s:\n    address: %s\n    disable_conn: true\n    db_name: %s\n", name, addr, db)
+	}
+	cfg, err := ParseConfig([]byte(data), "yaml")
+	require.NoError(t, err)
+	return cfg
+}
+
+// blockingInterceptor 在release关闭前阻塞CheckHealth，用于模拟进行中的操作
+func blockingInterceptor(started chan<- struct{}, release <-chan struct{}) client.Interceptor {
+	return func(ctx context.Context, info *client.OperationInfo, invoker client.Invoker) error {
+		if info.Name == "CheckHealth" {
+			started <- struct{}{}
+			<-release
+		}
+		return invoker(ctx, info)
+	}
+}
+
+// TestPoolReload 测试重新加载配置
+func TestPoolReload(t *testing.T) {
+	addr := testserver.Start(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("只重建有变化的客户端", func(t *testing.T) {
+		pl, err := NewPoolFromConfig(ctx, reloadConfig(t, addr, map[string]string{"same": "db1", "changed": "db1", "dropped": "db1"}))
+		require.NoError(t, err)
+		defer pl.Close()
+		require.NoError(t, pl.Add(ctx, "manual", client.WithAddress(addr), client.WithDisableConn(true)))
+
+		same, err := pl.Get("same")
+		require.NoError(t, err)
+		changed, err := pl.Get("changed")
+		require.NoError(t, err)
+		dropped, err := pl.Get("dropped")
+		require.NoError(t, err)
+
+		result, err := pl.Reload(ctx, reloadConfig(t, addr, map[string]string{"same": "db1", "changed": "db2", "added": "db1"}))
+		require.NoError(t, err)
+		assert.Equal(t, &ReloadResult{Added: []string{"added"}, Updated: []string{"changed"}, Removed: []string{"dropped"}}, result)
+
+		current, err := pl.Get("same")
+		require.NoError(t, err)
+		assert.Same(t, same, current, "配置未变化的客户端保持不变")
+
+		current, err = pl.Get("changed")
+		require.NoError(t, err)
+		assert.NotSame(t, changed, current)
+		cfg, err := pl.Config("changed")
+		require.NoError(t, err)
+		assert.Equal(t, "db2", cfg.DBName)
+		assert.ErrorIs(t, changed.CheckHealth(ctx), client.ErrClosed, "旧客户端已关闭")
+		assert.NoError(t, current.CheckHealth(ctx))
+
+		assert.False(t, pl.Has("dropped"))
+		assert.ErrorIs(t, dropped.CheckHealth(ctx), client.ErrClosed)
+		assert.True(t, pl.Has("added"))
+		assert.True(t, pl.Has("manual"), "通过Add添加的客户端不受影响")
+	})
+
+	t.Run("排空进行中的操作后关闭旧客户端", func(t *testing.T) {
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		pl, err := NewPoolFromConfig(ctx, reloadConfig(t, addr, map[string]string{"main": "db1"}),
+			WithClientOptions(client.WithInterceptors(blockingInterceptor(started, release))))
+		require.NoError(t, err)
+		defer pl.Close()
+
+		old, err := pl.Get("main")
+		require.NoError(t, err)
+		inflight := make(chan error, 1)
+		go func() { inflight <- old.CheckHealth(ctx) }()
+		<-started
+
+		reloaded := make(chan error, 1)
+		go func() {
+			_, err := pl.Reload(ctx, reloadConfig(t, addr, map[string]string{"main": "db2"}))
+			reloaded <- err
+		}()
+
+		// 替换立即生效，旧客户端等待操作完成
+		require.Eventually(t, func() bool {
+			cli, err := pl.Get("main")
+			return err == nil && cli != old
+		}, 5*time.Second, 5*time.Millisecond)
+		select {
+		case <-reloaded:
+			t.Fatal("旧客户端的操作完成前不应关闭")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		assert.NoError(t, <-inflight, "进行中的操作正常完成")
+		require.NoError(t, <-reloaded)
+		assert.ErrorIs(t, old.CheckHealth(ctx), client.ErrClosed)
+	})
+
+	t.Run("排空超时后关闭旧客户端", func(t *testing.T) {
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		pl, err := NewPoolFromConfig(ctx, reloadConfig(t, addr, map[string]string{"main": "db1"}),
+			WithDrainTimeout(50*time.Millisecond),
+			WithClientOptions(client.WithInterceptors(blockingInterceptor(started, release))))
+		require.NoError(t, err)
+		defer pl.Close()
+
+		old, err := pl.Get("main")
+		require.NoError(t, err)
+		go func() { _ = old.CheckHealth(ctx) }()
+		<-started
+
+		start := time.Now()
+		_, err = pl.Reload(ctx, reloadConfig(t, addr, map[string]string{"main": "db2"}))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		// 操作结束后旧客户端在后台关闭
+		release <- struct{}{}
+		require.Eventually(t, func() bool {
+			return errors.Is(old.CheckHealth(ctx), client.ErrClosed)
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("创建失败时连接池保持不变", func(t *testing.T) {
+		pl, err := NewPoolFromConfig(ctx, reloadConfig(t, addr, map[string]string{"main": "db1"}))
+		require.NoError(t, err)
+		defer pl.Close()
+		before, err := pl.Get("main")
+		require.NoError(t, err)
+
+		cfg, err := ParseConfig([]byte(fmt.Sprintf(`
+clients:
+  main:
+    address: %s
+    disable_conn: true
+    db_name: db2
+  bad:
+    address: 127.0.0.1:1
+`, addr)), "yaml")
+		require.NoError(t, err)
+		failCtx, failCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer failCancel()
+		_, err = pl.Reload(failCtx, cfg)
+		var cfgErr *ConfigError
+		require.ErrorAs(t, err, &cfgErr)
+		assert.Equal(t, "clients.bad", cfgErr.Key)
+
+		after, err := pl.Get("main")
+		require.NoError(t, err)
+		assert.Same(t, before, after)
+		assert.False(t, pl.Has("bad"))
+		current, err := pl.Config("main")
+		require.NoError(t, err)
+		assert.Equal(t, "db1", current.DBName)
+	})
+
+	t.Run("延迟连接时不创建客户端", func(t *testing.T) {
+		pl := NewPool(WithLazyConnect(0))
+		defer pl.Close()
+		result, err := pl.Reload(ctx, reloadConfig(t, "127.0.0.1:1", map[string]string{"main": "db1"}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"main"}, result.Added)
+		assert.True(t, pl.Has("main"))
+	})
+
+	t.Run("关闭后不能重新加载", func(t *testing.T) {
+		pl := NewPool()
+		require.NoError(t, pl.Close())
+		_, err := pl.Reload(ctx, reloadConfig(t, addr, map[string]string{"main": "db1"}))
+		assert.ErrorContains(t, err, "pool is closed")
+	})
+}
+
+// TestPoolRemoveDrain 测试移除与关闭时排空进行中的操作，期间不阻塞连接池的其他调用
+func TestPoolRemoveDrain(t *testing.T) {
+	addr := testserver.Start(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("移除时不阻塞其他调用", func(t *testing.T) {
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		pl := NewPool(WithDrainTimeout(time.Minute),
+			WithClientOptions(client.WithInterceptors(blockingInterceptor(started, release))))
+		defer pl.Close()
+		require.NoError(t, pl.Add(ctx, "main", client.WithAddress(addr), client.WithDisableConn(true)))
+		require.NoError(t, pl.Add(ctx, "other", client.WithAddress(addr), client.WithDisableConn(true)))
+
+		old, err := pl.Get("main")
+		require.NoError(t, err)
+		inflight := make(chan error, 1)
+		go func() { inflight <- old.CheckHealth(ctx) }()
+		<-started
+
+		removed := make(chan error, 1)
+		go func() { removed <- pl.Remove("main") }()
+
+		// 条目立即移除，排空期间其他调用不被阻塞
+		require.Eventually(t, func() bool { return !pl.Has("main") }, 5*time.Second, 5*time.Millisecond)
+		assert.Equal(t, []string{"other"}, pl.List())
+		_, err = pl.Health("other")
+		assert.NoError(t, err)
+		select {
+		case <-removed:
+			t.Fatal("旧客户端的操作完成前不应关闭")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		assert.NoError(t, <-inflight, "进行中的操作正常完成")
+		require.NoError(t, <-removed)
+		assert.ErrorIs(t, old.CheckHealth(ctx), client.ErrClosed)
+	})
+
+	t.Run("关闭时不阻塞其他调用", func(t *testing.T) {
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		pl := NewPool(WithClientOptions(client.WithInterceptors(blockingInterceptor(started, release))))
+		require.NoError(t, pl.Add(ctx, "main", client.WithAddress(addr), client.WithDisableConn(true)))
+
+		cli, err := pl.Get("main")
+		require.NoError(t, err)
+		inflight := make(chan error, 1)
+		go func() { inflight <- cli.CheckHealth(ctx) }()
+		<-started
+
+		closed := make(chan error, 1)
+		go func() { closed <- pl.Close() }()
+		require.Eventually(t, func() bool { return len(pl.List()) == 0 }, 5*time.Second, 5*time.Millisecond)
+		assert.False(t, pl.Has("main"))
+		assert.Error(t, pl.Add(ctx, "late", client.WithAddress(addr)), "关闭后不能再添加客户端")
+
+		close(release)
+		assert.NoError(t, <-inflight)
+		require.NoError(t, <-closed)
+		assert.ErrorIs(t, cli.CheckHealth(ctx), client.ErrClosed)
+	})
+}
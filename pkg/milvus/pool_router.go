@@ -0,0 +1,512 @@
+package milvus
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/pkg/errors"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+)
+
+// Balancer 读请求在多个读客户端之间的负载均衡策略
+type Balancer int
+
+const (
+	RoundRobin    Balancer = iota // 轮询
+	LeastInFlight                 // 选择进行中请求最少的客户端
+	LatencyAware                  // 选择延迟加权后负载最低的客户端，延迟为指数加权移动平均
+)
+
+// String 返回策略名称
+func (b Balancer) String() string {
+	switch b {
+	case RoundRobin:
+		return "round_robin"
+	case LeastInFlight:
+		return "least_in_flight"
+	case LatencyAware:
+		return "latency_aware"
+	default:
+		return "unknown"
+	}
+}
+
+// RouterOptions 读写分离路由的配置选项
+type RouterOptions struct {
+	Balancer       Balancer // 读请求的负载均衡策略
+	WriterFallback bool     // 所有读客户端都不健康时，读请求是否发送到写客户端
+	LatencyDecay   float64  // LatencyAware策略中延迟移动平均的衰减系数，取值(0, 1]，越大越偏向最近的延迟
+}
+
+// DefaultRouterOptions 返回默认的路由配置
+func DefaultRouterOptions() *RouterOptions {
+	return &RouterOptions{
+		Balancer:       RoundRobin,
+		WriterFallback: true, // 默认读客户端全部不可用时降级到写客户端
+		LatencyDecay:   0.3,
+	}
+}
+
+// RouterOption 定义路由配置选项函数类型
+type RouterOption func(*RouterOptions)
+
+// WithBalancer 设置读请求的负载均衡策略
+// balancer: RoundRobin、LeastInFlight或LatencyAware，默认RoundRobin
+func WithBalancer(balancer Balancer) RouterOption {
+	return func(o *RouterOptions) {
+		o.Balancer = balancer
+	}
+}
+
+// WithWriterFallback 设置所有读客户端都不健康时，读请求是否发送到写客户端
+// fallback: 默认true；false时仍在不健康的读客户端中选择
+func WithWriterFallback(fallback bool) RouterOption {
+	return func(o *RouterOptions) {
+		o.WriterFallback = fallback
+	}
+}
+
+// WithLatencyDecay 设置LatencyAware策略中延迟移动平均的衰减系数
+// decay: 取值(0, 1]，默认0.3
+func WithLatencyDecay(decay float64) RouterOption {
+	return func(o *RouterOptions) {
+		o.LatencyDecay = decay
+	}
+}
+
+// router 按读写分离将请求路由到连接池中的客户端，实现client.Client接口
+type router struct {
+	pool    Pool
+	writer  string
+	readers []*reader
+	options *RouterOptions
+
+	next   atomic.Uint64 // 轮询计数
+	closed atomic.Bool
+}
+
+// reader 一个读客户端的负载状态
+type reader struct {
+	name     string
+	inFlight atomic.Int64
+
+	mu      sync.Mutex
+	latency float64 // 延迟的指数加权移动平均，单位纳秒，0表示尚无数据
+}
+
+// load 返回LatencyAware策略的负载评分，尚无延迟数据的客户端优先
+func (r *reader) load() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.latency * float64(r.inFlight.Load()+1)
+}
+
+// observe 记录一次读请求的延迟，失败的请求按至少两倍的平均延迟计入，使出错的客户端负载评分升高
+func (r *reader) observe(elapsed time.Duration, err error, decay float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sample := float64(elapsed)
+	if err != nil && sample < 2*r.latency {
+		sample = 2 * r.latency
+	}
+	if r.latency == 0 {
+		r.latency = sample
+		return
+	}
+	r.latency = decay*sample + (1-decay)*r.latency
+}
+
+// NewRouter 创建读写分离路由，返回的客户端按操作类型将请求发送到连接池中的客户端
+//
+// Search、Query、QueryWithLimit按负载均衡策略发送到读客户端，只考虑连接池中健康的读客户端；
+// Insert、Delete、DDL以及其他元数据操作发送到写客户端。UseDatabase同时作用于写客户端与所有读客户端，
+// CheckHealth在写客户端健康且至少一个读客户端健康时返回nil。
+//
+// 每次请求时通过Pool.Get获取客户端，因此连接池重建或重新加载客户端后自动使用新客户端。
+// 路由不持有客户端，Close只停止路由，不关闭连接池中的客户端。
+// p: 连接池
+// writer: 写客户端名称
+// readers: 读客户端名称，为空时所有请求发送到写客户端
+// opts: 路由配置选项
+func NewRouter(p Pool, writer string, readers []string, opts ...RouterOption) (client.Client, error) {
+	options := DefaultRouterOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.LatencyDecay <= 0 || options.LatencyDecay > 1 {
+		return nil, errors.Errorf("latency decay must be in (0, 1], got %v", options.LatencyDecay)
+	}
+	if options.Balancer < RoundRobin || options.Balancer > LatencyAware {
+		return nil, errors.Errorf("unknown balancer %d", options.Balancer)
+	}
+
+	if !p.Has(writer) {
+		return nil, errors.Errorf("writer client %s not found", writer)
+	}
+	r := &router{pool: p, writer: writer, options: options}
+	seen := make(map[string]bool)
+	for _, name := range readers {
+		if !p.Has(name) {
+			return nil, errors.Errorf("reader client %s not found", name)
+		}
+		if seen[name] {
+			return nil, errors.Errorf("duplicate reader client %s", name)
+		}
+		seen[name] = true
+		r.readers = append(r.readers, &reader{name: name})
+	}
+	return r, nil
+}
+
+// candidates 返回健康的读客户端，没有时按配置返回nil（降级到写客户端）或所有读客户端
+func (r *router) candidates() []*reader {
+	healthy := make([]*reader, 0, len(r.readers))
+	for _, rd := range r.readers {
+		if status, err := r.pool.Health(rd.name); err == nil && status.Healthy {
+			healthy = append(healthy, rd)
+		}
+	}
+	if len(healthy) > 0 || r.options.WriterFallback {
+		return healthy
+	}
+	return r.readers
+}
+
+// pick 按负载均衡策略选择读客户端
+func (r *router) pick(candidates []*reader) *reader {
+	start := int(r.next.Add(1) % uint64(len(candidates)))
+	if r.options.Balancer == RoundRobin {
+		return candidates[start]
+	}
+
+	// 从轮询位置开始比较，负载相同时依次选择不同的客户端
+	best := candidates[start]
+	bestLoad := r.score(best)
+	for i := 1; i < len(candidates); i++ {
+		rd := candidates[(start+i)%len(candidates)]
+		if load := r.score(rd); load < bestLoad {
+			best, bestLoad = rd, load
+		}
+	}
+	return best
+}
+
+// score 返回客户端的负载评分，越小越优先
+func (r *router) score(rd *reader) float64 {
+	if r.options.Balancer == LatencyAware {
+		return rd.load()
+	}
+	return float64(rd.inFlight.Load())
+}
+
+// write 将请求发送到写客户端
+func (r *router) write(fn func(cli client.Client) error) error {
+	if r.closed.Load() {
+		return client.ErrClosed
+	}
+	cli, err := r.pool.Get(r.writer)
+	if err != nil {
+		return errors.Wrap(err, "failed to get writer client")
+	}
+	return fn(cli)
+}
+
+// read 将请求发送到按负载均衡策略选择的读客户端，没有可用的读客户端时发送到写客户端
+func (r *router) read(fn func(cli client.Client) error) error {
+	if r.closed.Load() {
+		return client.ErrClosed
+	}
+	candidates := r.candidates()
+	if len(candidates) == 0 {
+		return r.write(fn)
+	}
+
+	rd := r.pick(candidates)
+	cli, err := r.pool.Get(rd.name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get reader client %s", rd.name)
+	}
+	rd.inFlight.Add(1)
+	start := time.Now()
+	err = fn(cli)
+	rd.inFlight.Add(-1)
+	if !errors.Is(err, context.Canceled) {
+		rd.observe(time.Since(start), err, r.options.LatencyDecay)
+	}
+	return err
+}
+
+// GetClient 返回写客户端的原始Milvus客户端，获取失败时返回nil
+func (r *router) GetClient() *milvusclient.Client {
+	var raw *milvusclient.Client
+	_ = r.write(func(cli client.Client) error {
+		raw = cli.GetClient()
+		return nil
+	})
+	return raw
+}
+
+// CreateDatabase 在写客户端上创建数据库
+func (r *router) CreateDatabase(ctx context.Context, dbName string) error {
+	return r.write(func(cli client.Client) error { return cli.CreateDatabase(ctx, dbName) })
+}
+
+// DropDatabase 在写客户端上删除数据库
+func (r *router) DropDatabase(ctx context.Context, dbName string) error {
+	return r.write(func(cli client.Client) error { return cli.DropDatabase(ctx, dbName) })
+}
+
+// UseDatabase 切换写客户端与所有读客户端的数据库，读写请求在同一个数据库中执行
+// 连接池中的客户端是共享的，切换会影响通过连接池使用这些客户端的其他调用方
+func (r *router) UseDatabase(ctx context.Context, dbName string) error {
+	if err := r.write(func(cli client.Client) error { return cli.UseDatabase(ctx, dbName) }); err != nil {
+		return err
+	}
+	for _, rd := range r.readers {
+		cli, err := r.pool.Get(rd.name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get reader client %s", rd.name)
+		}
+		if err := cli.UseDatabase(ctx, dbName); err != nil {
+			return errors.Wrapf(err, "failed to use database on reader client %s", rd.name)
+		}
+	}
+	return nil
+}
+
+// ListDatabases 列出写客户端上的数据库
+func (r *router) ListDatabases(ctx context.Context) (dbs []string, err error) {
+	err = r.write(func(cli client.Client) error {
+		dbs, err = cli.ListDatabases(ctx)
+		return err
+	})
+	return dbs, err
+}
+
+// CreateCollection 在写客户端上创建集合
+func (r *router) CreateCollection(ctx context.Context, schema *entity.Schema, shardNum int32) error {
+	return r.write(func(cli client.Client) error { return cli.CreateCollection(ctx, schema, shardNum) })
+}
+
+// CreateCollectionWithOptions 在写客户端上使用选项创建集合
+func (r *router) CreateCollectionWithOptions(ctx context.Context, schema *entity.Schema, opts ...client.CollectionOption) error {
+	return r.write(func(cli client.Client) error { return cli.CreateCollectionWithOptions(ctx, schema, opts...) })
+}
+
+// DropCollection 在写客户端上删除集合
+func (r *router) DropCollection(ctx context.Context, collectionName string) error {
+	return r.write(func(cli client.Client) error { return cli.DropCollection(ctx, collectionName) })
+}
+
+// HasCollection 检查写客户端上集合是否存在
+func (r *router) HasCollection(ctx context.Context, collectionName string) (has bool, err error) {
+	err = r.write(func(cli client.Client) error {
+		has, err = cli.HasCollection(ctx, collectionName)
+		return err
+	})
+	return has, err
+}
+
+// LoadCollection 在写客户端上加载集合，读客户端所在集群的集合需要通过连接池分别加载
+func (r *router) LoadCollection(ctx context.Context, collectionName string) error {
+	return r.write(func(cli client.Client) error { return cli.LoadCollection(ctx, collectionName) })
+}
+
+// ReleaseCollection 在写客户端上释放集合
+func (r *router) ReleaseCollection(ctx context.Context, collectionName string) error {
+	return r.write(func(cli client.Client) error { return cli.ReleaseCollection(ctx, collectionName) })
+}
+
+// GetCollectionStatistics 获取写客户端上的集合统计信息
+func (r *router) GetCollectionStatistics(ctx context.Context, collectionName string) (stats map[string]string, err error) {
+	err = r.write(func(cli client.Client) error {
+		stats, err = cli.GetCollectionStatistics(ctx, collectionName)
+		return err
+	})
+	return stats, err
+}
+
+// DescribeCollection 获取写客户端上的集合信息
+func (r *router) DescribeCollection(ctx context.Context, collectionName string) (coll *entity.Collection, err error) {
+	err = r.write(func(cli client.Client) error {
+		coll, err = cli.DescribeCollection(ctx, collectionName)
+		return err
+	})
+	return coll, err
+}
+
+// ListCollections 列出写客户端上的集合
+func (r *router) ListCollections(ctx context.Context) (names []string, err error) {
+	err = r.write(func(cli client.Client) error {
+		names, err = cli.ListCollections(ctx)
+		return err
+	})
+	return names, err
+}
+
+// CreateAlias 在写客户端上创建别名
+func (r *router) CreateAlias(ctx context.Context, collectionName string, alias string) error {
+	return r.write(func(cli client.Client) error { return cli.CreateAlias(ctx, collectionName, alias) })
+}
+
+// DropAlias 在写客户端上删除别名
+func (r *router) DropAlias(ctx context.Context, alias string) error {
+	return r.write(func(cli client.Client) error { return cli.DropAlias(ctx, alias) })
+}
+
+// AlterAlias 在写客户端上修改别名
+func (r *router) AlterAlias(ctx context.Context, collectionName string, alias string) error {
+	return r.write(func(cli client.Client) error { return cli.AlterAlias(ctx, collectionName, alias) })
+}
+
+// ListAliases 列出写客户端上集合的别名
+func (r *router) ListAliases(ctx context.Context, collectionName string) (aliases []string, err error) {
+	err = r.write(func(cli client.Client) error {
+		aliases, err = cli.ListAliases(ctx, collectionName)
+		return err
+	})
+	return aliases, err
+}
+
+// CreatePartition 在写客户端上创建分区
+func (r *router) CreatePartition(ctx context.Context, collectionName string, partitionName string) error {
+	return r.write(func(cli client.Client) error { return cli.CreatePartition(ctx, collectionName, partitionName) })
+}
+
+// DropPartition 在写客户端上删除分区
+func (r *router) DropPartition(ctx context.Context, collectionName string, partitionName string) error {
+	return r.write(func(cli client.Client) error { return cli.DropPartition(ctx, collectionName, partitionName) })
+}
+
+// HasPartition 检查写客户端上分区是否存在
+func (r *router) HasPartition(ctx context.Context, collectionName string, partitionName string) (has bool, err error) {
+	err = r.write(func(cli client.Client) error {
+		has, err = cli.HasPartition(ctx, collectionName, partitionName)
+		return err
+	})
+	return has, err
+}
+
+// LoadPartitions 在写客户端上加载分区
+func (r *router) LoadPartitions(ctx context.Context, collectionName string, partitionNames []string) error {
+	return r.write(func(cli client.Client) error { return cli.LoadPartitions(ctx, collectionName, partitionNames) })
+}
+
+// ReleasePartitions 在写客户端上释放分区
+func (r *router) ReleasePartitions(ctx context.Context, collectionName string, partitionNames []string) error {
+	return r.write(func(cli client.Client) error { return cli.ReleasePartitions(ctx, collectionName, partitionNames) })
+}
+
+// ListPartitions 列出写客户端上集合的分区
+func (r *router) ListPartitions(ctx context.Context, collectionName string) (names []string, err error) {
+	err = r.write(func(cli client.Client) error {
+		names, err = cli.ListPartitions(ctx, collectionName)
+		return err
+	})
+	return names, err
+}
+
+// CreateIndex 在写客户端上创建索引
+func (r *router) CreateIndex(ctx context.Context, collectionName string, fieldName string, idx index.Index) error {
+	return r.write(func(cli client.Client) error { return cli.CreateIndex(ctx, collectionName, fieldName, idx) })
+}
+
+// DropIndex 在写客户端上删除索引
+func (r *router) DropIndex(ctx context.Context, collectionName string, fieldName string) error {
+	return r.write(func(cli client.Client) error { return cli.DropIndex(ctx, collectionName, fieldName) })
+}
+
+// DescribeIndex 获取写客户端上的索引信息
+func (r *router) DescribeIndex(ctx context.Context, collectionName string, fieldName string) (idx index.Index, err error) {
+	err = r.write(func(cli client.Client) error {
+		idx, err = cli.DescribeIndex(ctx, collectionName, fieldName)
+		return err
+	})
+	return idx, err
+}
+
+// Insert 通过写客户端插入数据
+func (r *router) Insert(ctx context.Context, collectionName string, partitionName string, columns ...column.Column) (ids column.Column, err error) {
+	err = r.write(func(cli client.Client) error {
+		ids, err = cli.Insert(ctx, collectionName, partitionName, columns...)
+		return err
+	})
+	return ids, err
+}
+
+// Delete 通过写客户端删除数据
+func (r *router) Delete(ctx context.Context, collectionName string, partitionName string, expr string) error {
+	return r.write(func(cli client.Client) error { return cli.Delete(ctx, collectionName, partitionName, expr) })
+}
+
+// Search 通过读客户端搜索
+func (r *router) Search(ctx context.Context, collectionName string, partitionNames []string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, expr string, params map[string]string) (results []milvusclient.ResultSet, err error) {
+	err = r.read(func(cli client.Client) error {
+		results, err = cli.Search(ctx, collectionName, partitionNames, outputFields, vectors, vectorField, metricType, topK, expr, params)
+		return err
+	})
+	return results, err
+}
+
+// Query 通过读客户端查询
+func (r *router) Query(ctx context.Context, collectionName string, partitionNames []string, expr string, outputFields []string) (columns []column.Column, err error) {
+	err = r.read(func(cli client.Client) error {
+		columns, err = cli.Query(ctx, collectionName, partitionNames, expr, outputFields)
+		return err
+	})
+	return columns, err
+}
+
+// QueryWithLimit 通过读客户端查询并限制返回数量
+func (r *router) QueryWithLimit(ctx context.Context, collectionName string, partitionNames []string, expr string, outputFields []string, limit int) (columns []column.Column, err error) {
+	err = r.read(func(cli client.Client) error {
+		columns, err = cli.QueryWithLimit(ctx, collectionName, partitionNames, expr, outputFields, limit)
+		return err
+	})
+	return columns, err
+}
+
+// Compact 通过写客户端压缩集合
+func (r *router) Compact(ctx context.Context, collectionName string) (id int64, err error) {
+	err = r.write(func(cli client.Client) error {
+		id, err = cli.Compact(ctx, collectionName)
+		return err
+	})
+	return id, err
+}
+
+// CheckHealth 检查写客户端与读客户端，写客户端健康且至少一个读客户端健康时返回nil
+func (r *router) CheckHealth(ctx context.Context) error {
+	if err := r.write(func(cli client.Client) error { return cli.CheckHealth(ctx) }); err != nil {
+		return errors.Wrap(err, "writer is unhealthy")
+	}
+	if len(r.readers) == 0 {
+		return nil
+	}
+	var lastErr error
+	for _, rd := range r.readers {
+		cli, err := r.pool.Get(rd.name)
+		if err == nil {
+			err = cli.CheckHealth(ctx)
+		}
+		if err == nil {
+			return nil
+		}
+		lastErr = errors.Wrapf(err, "reader %s is unhealthy", rd.name)
+	}
+	return errors.Wrap(lastErr, "no healthy reader")
+}
+
+// Close 停止路由，之后的请求返回client.ErrClosed，不关闭连接池中的客户端
+func (r *router) Close() error {
+	r.closed.Store(true)
+	return nil
+}
@@ -0,0 +1,272 @@
+package milvus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
+	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus/internal/testserver"
+)
+
+// fakeBackend 通过拦截器模拟一个集群，记录收到的操作且不发送请求到服务端
+type fakeBackend struct {
+	name string
+	log  *opLog
+
+	mu    sync.Mutex
+	err   error         // 返回的错误
+	delay time.Duration // 每次操作的耗时
+	block chan struct{} // 非nil时Search阻塞到关闭
+}
+
+// opLog 按顺序记录各集群收到的操作
+type opLog struct {
+	mu  sync.Mutex
+	ops []string
+}
+
+// add 记录一次操作
+func (l *opLog) add(backend, op string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ops = append(l.ops, backend+":"+op)
+}
+
+// take 返回并清空记录
+func (l *opLog) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ops := l.ops
+	l.ops = nil
+	return ops
+}
+
+// set 设置集群的行为
+func (b *fakeBackend) set(err error, delay time.Duration, block chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.err, b.delay, b.block = err, delay, block
+}
+
+// interceptor 记录操作并按设置返回
+func (b *fakeBackend) interceptor() client.Interceptor {
+	return func(ctx context.Context, info *client.OperationInfo, invoker client.Invoker) error {
+		b.mu.Lock()
+		err, delay, block := b.err, b.delay, b.block
+		b.mu.Unlock()
+
+		b.log.add(b.name, info.Name)
+		if block != nil && info.Name == "Search" {
+			<-block
+		}
+		time.Sleep(delay)
+		return err
+	}
+}
+
+// newRouterPool 创建包含writer、r1、r2三个客户端的连接池
+func newRouterPool(t *testing.T) (Pool, map[string]*fakeBackend, *opLog) {
+	addr := testserver.Start(t)
+	pl := NewPool()
+	t.Cleanup(func() { _ = pl.Close() })
+	log := &opLog{}
+	backends := make(map[string]*fakeBackend)
+	for _, name := range []string{"writer", "r1", "r2"} {
+		b := &fakeBackend{name: name, log: log}
+		backends[name] = b
+		require.NoError(t, pl.Add(context.Background(), name, client.WithAddress(addr), client.WithDisableConn(true),
+			client.WithInterceptors(b.interceptor()), client.WithRetryPolicy(client.OpRead, nil)))
+	}
+	return pl, backends, log
+}
+
+// search 通过路由发起一次搜索
+func search(ctx context.Context, cli client.Client) error {
+	_, err := cli.Search(ctx, "docs", nil, nil, []entity.Vector{entity.FloatVector{0.1, 0.2}}, "vector", entity.L2, 10, "", nil)
+	return err
+}
+
+// TestRouter 测试读写分离路由
+func TestRouter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("读写分离", func(t *testing.T) {
+		pl, _, log := newRouterPool(t)
+		rt, err := NewRouter(pl, "writer", []string{"r1", "r2"})
+		require.NoError(t, err)
+
+		_, err = rt.Insert(ctx, "docs", "", column.NewColumnInt64("id", []int64{1}))
+		require.NoError(t, err)
+		require.NoError(t, rt.Delete(ctx, "docs", "", "id in [1]"))
+		require.NoError(t, rt.DropCollection(ctx, "docs"))
+		_, err = rt.HasCollection(ctx, "docs")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"writer:Insert", "writer:Delete", "writer:DropCollection", "writer:HasCollection"}, log.take())
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, search(ctx, rt))
+		}
+		_, err = rt.Query(ctx, "docs", nil, "id > 0", nil)
+		require.NoError(t, err)
+		_, err = rt.QueryWithLimit(ctx, "docs", nil, "id > 0", nil, 10)
+		require.NoError(t, err)
+		ops := log.take()
+		assert.Equal(t, []string{"r2:Search", "r1:Search", "r2:Search", "r1:Search", "r2:Query", "r1:QueryWithLimit"}, ops, "轮询读客户端")
+	})
+
+	t.Run("没有读客户端时发送到写客户端", func(t *testing.T) {
+		pl, _, log := newRouterPool(t)
+		rt, err := NewRouter(pl, "writer", nil)
+		require.NoError(t, err)
+		require.NoError(t, search(ctx, rt))
+		assert.Equal(t, []string{"writer:Search"}, log.take())
+	})
+
+	t.Run("选择进行中请求最少的读客户端", func(t *testing.T) {
+		pl, backends, log := newRouterPool(t)
+		rt, err := NewRouter(pl, "writer", []string{"r1", "r2"}, WithBalancer(LeastInFlight))
+		require.NoError(t, err)
+
+		// 第一次请求发送到r2并阻塞，之后的请求都发送到r1
+		block := make(chan struct{})
+		backends["r2"].set(nil, 0, block)
+		done := make(chan error, 1)
+		go func() { done <- search(ctx, rt) }()
+		require.Eventually(t, func() bool { return len(log.take()) == 1 }, time.Second, time.Millisecond)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, search(ctx, rt))
+		}
+		assert.Equal(t, []string{"r1:Search", "r1:Search", "r1:Search"}, log.take())
+		close(block)
+		require.NoError(t, <-done)
+	})
+
+	t.Run("选择延迟较低的读客户端", func(t *testing.T) {
+		pl, backends, log := newRouterPool(t)
+		rt, err := NewRouter(pl, "writer", []string{"r1", "r2"}, WithBalancer(LatencyAware), WithLatencyDecay(0.5))
+		require.NoError(t, err)
+		backends["r1"].set(nil, 30*time.Millisecond, nil)
+		backends["r2"].set(nil, time.Millisecond, nil)
+
+		for i := 0; i < 20; i++ {
+			require.NoError(t, search(ctx, rt))
+		}
+		counts := map[string]int{}
+		for _, op := range log.take() {
+			counts[op]++
+		}
+		assert.Greater(t, counts["r2:Search"], 15, "大部分请求发送到延迟较低的r2: %v", counts)
+	})
+
+	t.Run("跳过不健康的读客户端", func(t *testing.T) {
+		pl, backends, log := newRouterPool(t)
+		rt, err := NewRouter(pl, "writer", []string{"r1", "r2"})
+		require.NoError(t, err)
+
+		// r2返回服务不可用后被标记为不健康
+		backends["r2"].set(client.ErrUnavailable, 0, nil)
+		assert.ErrorIs(t, search(ctx, rt), client.ErrUnavailable)
+		log.take()
+		for i := 0; i < 3; i++ {
+			require.NoError(t, search(ctx, rt))
+		}
+		assert.Equal(t, []string{"r1:Search", "r1:Search", "r1:Search"}, log.take())
+
+		// 所有读客户端都不健康时降级到写客户端
+		backends["r1"].set(client.ErrUnavailable, 0, nil)
+		assert.Error(t, search(ctx, rt))
+		log.take()
+		require.NoError(t, search(ctx, rt))
+		assert.Equal(t, []string{"writer:Search"}, log.take())
+
+		t.Run("不降级时仍使用读客户端", func(t *testing.T) {
+			rt, err := NewRouter(pl, "writer", []string{"r1", "r2"}, WithWriterFallback(false))
+			require.NoError(t, err)
+			assert.ErrorIs(t, search(ctx, rt), client.ErrUnavailable)
+			ops := log.take()
+			require.Len(t, ops, 1)
+			assert.NotEqual(t, "writer:Search", ops[0])
+		})
+	})
+
+	t.Run("切换数据库作用于所有客户端", func(t *testing.T) {
+		pl, _, log := newRouterPool(t)
+		rt, err := NewRouter(pl, "writer", []string{"r1", "r2"})
+		require.NoError(t, err)
+		require.NoError(t, rt.UseDatabase(ctx, "analytics"))
+		assert.Equal(t, []string{"writer:UseDatabase", "r1:UseDatabase", "r2:UseDatabase"}, log.take())
+	})
+
+	t.Run("健康检查", func(t *testing.T) {
+		pl, backends, _ := newRouterPool(t)
+		rt, err := NewRouter(pl, "writer", []string{"r1", "r2"})
+		require.NoError(t, err)
+		require.NoError(t, rt.CheckHealth(ctx))
+
+		backends["r1"].set(client.ErrUnavailable, 0, nil)
+		assert.NoError(t, rt.CheckHealth(ctx), "至少一个读客户端健康")
+		backends["r2"].set(client.ErrUnavailable, 0, nil)
+		assert.ErrorContains(t, rt.CheckHealth(ctx), "no healthy reader")
+
+		backends["writer"].set(client.ErrUnavailable, 0, nil)
+		assert.ErrorContains(t, rt.CheckHealth(ctx), "writer is unhealthy")
+	})
+
+	t.Run("关闭路由不关闭客户端", func(t *testing.T) {
+		pl, _, _ := newRouterPool(t)
+		rt, err := NewRouter(pl, "writer", []string{"r1"})
+		require.NoError(t, err)
+		require.NoError(t, rt.Close())
+		assert.ErrorIs(t, search(ctx, rt), client.ErrClosed)
+		assert.ErrorIs(t, rt.DropCollection(ctx, "docs"), client.ErrClosed)
+
+		cli, err := pl.Get("r1")
+		require.NoError(t, err)
+		assert.NoError(t, cli.CheckHealth(ctx))
+	})
+
+	t.Run("无效配置", func(t *testing.T) {
+		pl, _, _ := newRouterPool(t)
+		tests := []struct {
+			writer  string
+			readers []string
+			opts    []RouterOption
+			want    string
+		}{
+			{"missing", nil, nil, "writer client missing not found"},
+			{"writer", []string{"r1", "missing"}, nil, "reader client missing not found"},
+			{"writer", []string{"r1", "r1"}, nil, "duplicate reader client r1"},
+			{"writer", nil, []RouterOption{WithLatencyDecay(0)}, "latency decay"},
+			{"writer", nil, []RouterOption{WithBalancer(Balancer(9))}, "unknown balancer"},
+		}
+		for _, tt := range tests {
+			_, err := NewRouter(pl, tt.writer, tt.readers, tt.opts...)
+			assert.ErrorContains(t, err, tt.want)
+		}
+	})
+}
+
+// TestReaderObserve 测试读客户端延迟的移动平均
+func TestReaderObserve(t *testing.T) {
+	rd := &reader{name: "r1"}
+	rd.observe(100*time.Millisecond, nil, 0.5)
+	assert.Equal(t, float64(100*time.Millisecond), rd.load(), "首次记录直接使用样本")
+	rd.observe(200*time.Millisecond, nil, 0.5)
+	assert.Equal(t, float64(150*time.Millisecond), rd.load())
+
+	// 快速失败按两倍平均延迟计入
+	rd.observe(time.Millisecond, errors.New("unavailable"), 0.5)
+	assert.Equal(t, float64(225*time.Millisecond), rd.load())
+
+	rd.inFlight.Add(1)
+	assert.Equal(t, float64(450*time.Millisecond), rd.load(), "进行中的请求提高负载评分")
+}